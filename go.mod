@@ -21,6 +21,7 @@ require (
 	github.com/twpayne/pgx-geom v0.0.2
 	github.com/wisdom-oss/common-go/v3 v3.2.0
 	github.com/wisdom-oss/go-healthcheck v1.0.5
+	golang.org/x/text v0.23.0
 
 )
 
@@ -78,7 +79,6 @@ require (
 	golang.org/x/net v0.37.0 // indirect
 	golang.org/x/sync v0.12.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
-	golang.org/x/text v0.23.0 // indirect
 	golang.org/x/xerrors v0.0.0-20231012003039-104605ab7028 // indirect
 	google.golang.org/protobuf v1.36.5 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
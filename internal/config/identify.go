@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// IdentifyMaxKeys is the maximal number of keys which may be identified in a
+// single request. It is read from the `IDENTIFY_MAX_KEYS` environment variable
+// and defaults to 100.
+var IdentifyMaxKeys = 100
+
+func init() {
+	rawMaxKeys, isSet := os.LookupEnv("IDENTIFY_MAX_KEYS")
+	if !isSet {
+		return
+	}
+
+	maxKeys, err := strconv.Atoi(rawMaxKeys)
+	if err != nil || maxKeys < 1 {
+		log.Warn().Str("value", rawMaxKeys).Msg("invalid maximal key count for identify requests. using default")
+		return
+	}
+	IdentifyMaxKeys = maxKeys
+}
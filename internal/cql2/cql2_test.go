@@ -0,0 +1,118 @@
+package cql2_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"microservice/internal/cql2"
+)
+
+// translate converts the expression into SQL and returns the condition with
+// the registered arguments.
+func translate(t *testing.T, expression cql2.Expression) (string, []any) {
+	t.Helper()
+	var args []any
+	condition, err := expression.SQL(func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return condition, args
+}
+
+func Test_ParseText_Comparison(t *testing.T) {
+	expression, err := cql2.ParseText(`depth > 50 AND category = 'II'`)
+	assert.NoError(t, err)
+
+	condition, args := translate(t, expression)
+	assert.Equal(t, "((CASE WHEN jsonb_typeof(additional_properties -> $1::text) = 'number' THEN (additional_properties ->> $1::text)::double precision END) > $2::double precision"+
+		" AND (CASE WHEN jsonb_typeof(additional_properties -> $3::text) = 'string' THEN (additional_properties ->> $3::text)::text END) = $4::text)", condition)
+	assert.Equal(t, []any{"depth", 50.0, "category", "II"}, args)
+}
+
+func Test_ParseText_Columns(t *testing.T) {
+	expression, err := cql2.ParseText(`key LIKE '031%' OR NOT (name IS NULL)`)
+	assert.NoError(t, err)
+
+	condition, args := translate(t, expression)
+	assert.Equal(t, "(key LIKE $1::text OR NOT name IS NULL)", condition)
+	assert.Equal(t, []any{"031%"}, args)
+}
+
+func Test_ParseText_AdvancedComparison(t *testing.T) {
+	expression, err := cql2.ParseText(`depth NOT BETWEEN 10 AND 20 AND "type" IN ('a', 'b') AND remark IS NOT NULL`)
+	assert.NoError(t, err)
+
+	condition, args := translate(t, expression)
+	assert.Contains(t, condition, "NOT (CASE WHEN jsonb_typeof(additional_properties -> $1::text) = 'number'")
+	assert.Contains(t, condition, "BETWEEN $2::double precision AND $3::double precision")
+	assert.Contains(t, condition, "IN ($5::text, $6::text)")
+	assert.Contains(t, condition, "NOT coalesce(jsonb_typeof(additional_properties -> $7::text), 'null') = 'null'")
+	assert.Equal(t, []any{"depth", 10.0, 20.0, "type", "a", "b", "remark"}, args)
+}
+
+func Test_ParseText_EscapedQuotes(t *testing.T) {
+	expression, err := cql2.ParseText(`name = 'Robert''); DROP TABLE layers; --'`)
+	assert.NoError(t, err)
+
+	condition, args := translate(t, expression)
+	assert.Equal(t, "name = $1::text", condition)
+	assert.Equal(t, []any{"Robert'); DROP TABLE layers; --"}, args)
+}
+
+func Test_ParseText_SyntaxErrors(t *testing.T) {
+	filters := []string{
+		`depth >`,
+		`depth > 50 AND`,
+		`(depth > 50`,
+		`name = 'unterminated`,
+		`depth 50`,
+		`depth NOT 50`,
+		`depth ; 50`,
+	}
+	for _, filter := range filters {
+		_, err := cql2.ParseText(filter)
+		assert.ErrorIs(t, err, cql2.ErrSyntax, filter)
+	}
+}
+
+func Test_ParseJSON(t *testing.T) {
+	expression, err := cql2.ParseJSON([]byte(`{
+		"op": "and",
+		"args": [
+			{"op": ">=", "args": [{"property": "depth"}, 50]},
+			{"op": "in", "args": [{"property": "key"}, ["03101", "03102"]]},
+			{"op": "not", "args": [{"op": "isNull", "args": [{"property": "operator"}]}]}
+		]
+	}`))
+	assert.NoError(t, err)
+
+	condition, args := translate(t, expression)
+	assert.Equal(t, "((CASE WHEN jsonb_typeof(additional_properties -> $1::text) = 'number' THEN (additional_properties ->> $1::text)::double precision END) >= $2::double precision"+
+		" AND key IN ($3::text, $4::text)"+
+		" AND NOT coalesce(jsonb_typeof(additional_properties -> $5::text), 'null') = 'null')", condition)
+	assert.Equal(t, []any{"depth", 50.0, "03101", "03102", "operator"}, args)
+}
+
+func Test_SQL_InvalidExpressions(t *testing.T) {
+	expressions := []string{
+		`{"op": "drop", "args": [{"property": "depth"}, 50]}`,
+		`{"op": "=", "args": [{"property": "depth"}]}`,
+		`{"op": "=", "args": [{"property": "key"}, 5]}`,
+		`{"op": "like", "args": [{"property": "depth"}, 5]}`,
+		`{"op": "and", "args": [true]}`,
+		`"depth"`,
+	}
+	for _, rawExpression := range expressions {
+		expression, err := cql2.ParseJSON([]byte(rawExpression))
+		if !assert.NoError(t, err, rawExpression) {
+			continue
+		}
+		_, err = expression.SQL(func(any) string { return "$1" })
+		assert.ErrorIs(t, err, cql2.ErrInvalidExpression, rawExpression)
+	}
+}
@@ -0,0 +1,324 @@
+// Package cql2 parses filters written in the OGC Common Query Language (CQL2)
+// and translates them into parameterized SQL conditions on the objects of a
+// layer.
+//
+// The package supports the text and JSON encodings of the "Basic CQL2" and
+// "Advanced Comparison Operators" conformance classes. The properties "id",
+// "key" and "name" reference the columns of the layer table, while every
+// other property references an entry in the additional properties of the
+// objects.
+package cql2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The operators supported in expressions. The names match the operators used
+// in the JSON encoding.
+const (
+	OpAnd     = "and"
+	OpOr      = "or"
+	OpNot     = "not"
+	OpEqual   = "="
+	OpUnequal = "<>"
+	OpLess    = "<"
+	OpGreater = ">"
+	OpLessEq  = "<="
+	OpGreatEq = ">="
+	OpLike    = "like"
+	OpBetween = "between"
+	OpIn      = "in"
+	OpIsNull  = "isNull"
+)
+
+// ErrInvalidExpression is returned if an expression is structurally invalid,
+// e.g. if an operator has the wrong number of arguments.
+var ErrInvalidExpression = errors.New("invalid cql2 expression")
+
+type kind int
+
+const (
+	kindOperation kind = iota
+	kindProperty
+	kindLiteral
+	kindList
+)
+
+// Expression is a node in a parsed CQL2 filter. It is either an operation
+// with its arguments, a reference to a property, a literal value or a list
+// of literal values.
+type Expression struct {
+	kind kind
+
+	// Op contains the operator of an operation
+	Op string
+
+	// Args contains the arguments of an operation or the entries of a list
+	Args []Expression
+
+	// Property contains the name of the referenced property
+	Property string
+
+	// Value contains the literal value which is either a string, a float64
+	// or a bool
+	Value any
+}
+
+// Operation creates a new operation using the arguments.
+func Operation(op string, args ...Expression) Expression {
+	return Expression{kind: kindOperation, Op: op, Args: args}
+}
+
+// Property creates a new reference to a property.
+func Property(name string) Expression {
+	return Expression{kind: kindProperty, Property: name}
+}
+
+// Literal creates a new literal value.
+func Literal(value any) Expression {
+	return Expression{kind: kindLiteral, Value: value}
+}
+
+// List creates a new list of literal values.
+func List(values ...Expression) Expression {
+	return Expression{kind: kindList, Args: values}
+}
+
+// columns contains the properties which are stored in a column of the layer
+// table with the type of the column.
+var columns = map[string]string{
+	"id":   "number",
+	"key":  "string",
+	"name": "string",
+}
+
+// jsonTypes maps the types of literals to the SQL type the values of the
+// additional properties are cast into.
+var jsonTypes = map[string]string{
+	"number":  "double precision",
+	"string":  "text",
+	"boolean": "boolean",
+}
+
+// literalType returns the JSON type of a literal.
+func literalType(value any) string {
+	switch value.(type) {
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// SQL translates the expression into an SQL condition. The literals and
+// property names are never embedded into the condition. Instead, they are
+// registered using arg which returns the placeholder used in the condition.
+func (e Expression) SQL(arg func(value any) string) (string, error) {
+	t := translator{arg: arg}
+	return t.condition(e)
+}
+
+type translator struct {
+	arg func(value any) string
+}
+
+// condition translates an expression which results in a boolean value.
+func (t translator) condition(e Expression) (string, error) {
+	switch e.kind {
+	case kindLiteral:
+		value, isBool := e.Value.(bool)
+		if !isBool {
+			return "", fmt.Errorf("%w: literal '%v' is not a boolean", ErrInvalidExpression, e.Value)
+		}
+		if value {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case kindOperation:
+		break
+	default:
+		return "", fmt.Errorf("%w: expected a boolean expression", ErrInvalidExpression)
+	}
+
+	switch e.Op {
+	case OpAnd, OpOr:
+		if len(e.Args) < 2 {
+			return "", fmt.Errorf("%w: '%s' requires at least two arguments", ErrInvalidExpression, e.Op)
+		}
+		conditions := make([]string, len(e.Args))
+		for idx, argument := range e.Args {
+			condition, err := t.condition(argument)
+			if err != nil {
+				return "", err
+			}
+			conditions[idx] = condition
+		}
+		return "(" + strings.Join(conditions, " "+strings.ToUpper(e.Op)+" ") + ")", nil
+	case OpNot:
+		if len(e.Args) != 1 {
+			return "", fmt.Errorf("%w: 'not' requires exactly one argument", ErrInvalidExpression)
+		}
+		condition, err := t.condition(e.Args[0])
+		if err != nil {
+			return "", err
+		}
+		return "NOT " + condition, nil
+	case OpEqual, OpUnequal, OpLess, OpGreater, OpLessEq, OpGreatEq:
+		return t.comparison(e)
+	case OpLike:
+		return t.like(e)
+	case OpBetween:
+		return t.between(e)
+	case OpIn:
+		return t.in(e)
+	case OpIsNull:
+		return t.isNull(e)
+	default:
+		return "", fmt.Errorf("%w: unsupported operator '%s'", ErrInvalidExpression, e.Op)
+	}
+}
+
+// operandType returns the type used to compare the operands. The type is
+// determined by the first literal. If no literal is used, the operands are
+// compared as JSON values.
+func operandType(operands ...Expression) string {
+	for _, operand := range operands {
+		switch operand.kind {
+		case kindLiteral:
+			return literalType(operand.Value)
+		case kindList:
+			if len(operand.Args) > 0 {
+				return operandType(operand.Args...)
+			}
+		}
+	}
+	return "jsonb"
+}
+
+// scalar translates a property or literal into a value of the type.
+// Additional properties which have another JSON type are translated into
+// NULL, which never fulfills a comparison.
+func (t translator) scalar(e Expression, valueType string) (string, error) {
+	switch e.kind {
+	case kindLiteral:
+		if valueType == "jsonb" {
+			return "to_jsonb(" + t.arg(e.Value) + "::" + jsonTypes[literalType(e.Value)] + ")", nil
+		}
+		if literalType(e.Value) != valueType {
+			return "", fmt.Errorf("%w: cannot compare '%v' with a %s", ErrInvalidExpression, e.Value, valueType)
+		}
+		return t.arg(e.Value) + "::" + jsonTypes[valueType], nil
+	case kindProperty:
+		if columnType, isColumn := columns[e.Property]; isColumn {
+			if valueType == "jsonb" {
+				return "to_jsonb(" + e.Property + ")", nil
+			}
+			if columnType != valueType {
+				return "", fmt.Errorf("%w: property '%s' cannot be compared with a %s", ErrInvalidExpression, e.Property, valueType)
+			}
+			return e.Property, nil
+		}
+
+		property := t.arg(e.Property) + "::text"
+		if valueType == "jsonb" {
+			return "(additional_properties -> " + property + ")", nil
+		}
+		// the type is checked before casting as the cast fails on values of
+		// other types
+		return fmt.Sprintf("(CASE WHEN jsonb_typeof(additional_properties -> %s) = '%s' THEN (additional_properties ->> %s)::%s END)",
+			property, valueType, property, jsonTypes[valueType]), nil
+	default:
+		return "", fmt.Errorf("%w: expected a property or a literal", ErrInvalidExpression)
+	}
+}
+
+func (t translator) comparison(e Expression) (string, error) {
+	if len(e.Args) != 2 {
+		return "", fmt.Errorf("%w: '%s' requires exactly two arguments", ErrInvalidExpression, e.Op)
+	}
+
+	valueType := operandType(e.Args...)
+	left, err := t.scalar(e.Args[0], valueType)
+	if err != nil {
+		return "", err
+	}
+	right, err := t.scalar(e.Args[1], valueType)
+	if err != nil {
+		return "", err
+	}
+	return left + " " + e.Op + " " + right, nil
+}
+
+func (t translator) like(e Expression) (string, error) {
+	if len(e.Args) != 2 {
+		return "", fmt.Errorf("%w: 'like' requires exactly two arguments", ErrInvalidExpression)
+	}
+
+	value, err := t.scalar(e.Args[0], "string")
+	if err != nil {
+		return "", err
+	}
+	pattern, err := t.scalar(e.Args[1], "string")
+	if err != nil {
+		return "", err
+	}
+	return value + " LIKE " + pattern, nil
+}
+
+func (t translator) between(e Expression) (string, error) {
+	if len(e.Args) != 3 {
+		return "", fmt.Errorf("%w: 'between' requires exactly three arguments", ErrInvalidExpression)
+	}
+
+	values := make([]string, len(e.Args))
+	for idx, argument := range e.Args {
+		value, err := t.scalar(argument, "number")
+		if err != nil {
+			return "", err
+		}
+		values[idx] = value
+	}
+	return values[0] + " BETWEEN " + values[1] + " AND " + values[2], nil
+}
+
+func (t translator) in(e Expression) (string, error) {
+	if len(e.Args) != 2 || e.Args[1].kind != kindList || len(e.Args[1].Args) == 0 {
+		return "", fmt.Errorf("%w: 'in' requires a value and a non-empty list", ErrInvalidExpression)
+	}
+
+	valueType := operandType(e.Args[1])
+	value, err := t.scalar(e.Args[0], valueType)
+	if err != nil {
+		return "", err
+	}
+
+	entries := make([]string, len(e.Args[1].Args))
+	for idx, entry := range e.Args[1].Args {
+		if entry.kind != kindLiteral {
+			return "", fmt.Errorf("%w: 'in' only supports lists of literals", ErrInvalidExpression)
+		}
+		entries[idx], err = t.scalar(entry, valueType)
+		if err != nil {
+			return "", err
+		}
+	}
+	return value + " IN (" + strings.Join(entries, ", ") + ")", nil
+}
+
+func (t translator) isNull(e Expression) (string, error) {
+	if len(e.Args) != 1 || e.Args[0].kind != kindProperty {
+		return "", fmt.Errorf("%w: 'isNull' requires exactly one property", ErrInvalidExpression)
+	}
+
+	property := e.Args[0].Property
+	if _, isColumn := columns[property]; isColumn {
+		return property + " IS NULL", nil
+	}
+
+	placeholder := t.arg(property) + "::text"
+	return fmt.Sprintf("coalesce(jsonb_typeof(additional_properties -> %s), 'null') = 'null'", placeholder), nil
+}
@@ -0,0 +1,67 @@
+package cql2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ParseJSON parses a filter in the CQL2 JSON encoding.
+func ParseJSON(filter []byte) (Expression, error) {
+	var expression Expression
+	if err := json.Unmarshal(filter, &expression); err != nil {
+		return Expression{}, err
+	}
+	return expression, nil
+}
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface and decodes an
+// expression from the CQL2 JSON encoding.
+func (e *Expression) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return fmt.Errorf("%w: empty expression", ErrInvalidExpression)
+	}
+
+	switch data[0] {
+	case '[':
+		var entries []Expression
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return err
+		}
+		*e = List(entries...)
+		return nil
+	case '{':
+		var object struct {
+			Op       *string      `json:"op"`
+			Args     []Expression `json:"args"`
+			Property *string      `json:"property"`
+		}
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+
+		switch {
+		case object.Op != nil:
+			*e = Operation(*object.Op, object.Args...)
+		case object.Property != nil:
+			*e = Property(*object.Property)
+		default:
+			return fmt.Errorf("%w: objects need to be operations or properties", ErrInvalidExpression)
+		}
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch value.(type) {
+	case string, float64, bool:
+		*e = Literal(value)
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported literal %s", ErrInvalidExpression, data)
+	}
+}
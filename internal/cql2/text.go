@@ -0,0 +1,360 @@
+package cql2
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrSyntax is returned if a filter in the text encoding is not valid CQL2.
+var ErrSyntax = errors.New("invalid cql2 syntax")
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenOpenParenthesis
+	tokenCloseParenthesis
+	tokenComma
+)
+
+type token struct {
+	typ      tokenType
+	value    string
+	position int
+
+	// quoted is set for identifiers written in double quotes which are
+	// never interpreted as keywords
+	quoted bool
+}
+
+// tokenize splits the filter into its tokens. Identifiers are returned
+// without the optional double quotes and strings without the single quotes.
+func tokenize(filter string) ([]token, error) {
+	var tokens []token
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{typ: tokenOpenParenthesis, value: "(", position: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{typ: tokenCloseParenthesis, value: ")", position: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{typ: tokenComma, value: ",", position: i})
+			i++
+		case r == '=':
+			tokens = append(tokens, token{typ: tokenOperator, value: "=", position: i})
+			i++
+		case r == '<' || r == '>':
+			operator := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '<' && runes[i+1] == '>')) {
+				operator += string(runes[i+1])
+			}
+			tokens = append(tokens, token{typ: tokenOperator, value: operator, position: i})
+			i += len(operator)
+		case r == '\'' || r == '"':
+			value, end, err := quoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			typ := tokenString
+			if r == '"' {
+				typ = tokenIdentifier
+			}
+			tokens = append(tokens, token{typ: typ, value: value, position: i, quoted: r == '"'})
+			i = end
+		case unicode.IsDigit(r) || r == '-' || r == '+' || r == '.':
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenNumber, value: string(runes[start:i]), position: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_.:", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenIdentifier, value: string(runes[start:i]), position: start})
+		default:
+			return nil, fmt.Errorf("%w: unexpected character '%c' at position %d", ErrSyntax, r, i)
+		}
+	}
+	return append(tokens, token{typ: tokenEOF, position: len(runes)}), nil
+}
+
+// quoted reads a quoted string starting at the position. Quotes are escaped
+// by doubling them.
+func quoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var value strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			value.WriteRune(runes[i])
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			value.WriteRune(quote)
+			i++
+			continue
+		}
+		return value.String(), i + 1, nil
+	}
+	return "", 0, fmt.Errorf("%w: unterminated quote at position %d", ErrSyntax, start)
+}
+
+// textParser is a recursive descent parser for the text encoding.
+type textParser struct {
+	tokens   []token
+	position int
+}
+
+func (p *textParser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *textParser) next() token {
+	t := p.tokens[p.position]
+	if t.typ != tokenEOF {
+		p.position++
+	}
+	return t
+}
+
+// keyword checks if the next token is the keyword and consumes it.
+func (p *textParser) keyword(keyword string) bool {
+	t := p.peek()
+	if t.typ == tokenIdentifier && !t.quoted && strings.EqualFold(t.value, keyword) {
+		p.position++
+		return true
+	}
+	return false
+}
+
+func (p *textParser) expect(typ tokenType, description string) (token, error) {
+	t := p.next()
+	if t.typ != typ {
+		return t, fmt.Errorf("%w: expected %s at position %d", ErrSyntax, description, t.position)
+	}
+	return t, nil
+}
+
+// ParseText parses a filter in the CQL2 text encoding.
+func ParseText(filter string) (Expression, error) {
+	tokens, err := tokenize(filter)
+	if err != nil {
+		return Expression{}, err
+	}
+
+	p := &textParser{tokens: tokens}
+	expression, err := p.or()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	if t := p.peek(); t.typ != tokenEOF {
+		return Expression{}, fmt.Errorf("%w: unexpected '%s' at position %d", ErrSyntax, t.value, t.position)
+	}
+	return expression, nil
+}
+
+func (p *textParser) or() (Expression, error) {
+	expression, err := p.and()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	args := []Expression{expression}
+	for p.keyword("OR") {
+		expression, err = p.and()
+		if err != nil {
+			return Expression{}, err
+		}
+		args = append(args, expression)
+	}
+
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return Operation(OpOr, args...), nil
+}
+
+func (p *textParser) and() (Expression, error) {
+	expression, err := p.not()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	args := []Expression{expression}
+	for p.keyword("AND") {
+		expression, err = p.not()
+		if err != nil {
+			return Expression{}, err
+		}
+		args = append(args, expression)
+	}
+
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return Operation(OpAnd, args...), nil
+}
+
+func (p *textParser) not() (Expression, error) {
+	if p.keyword("NOT") {
+		expression, err := p.not()
+		if err != nil {
+			return Expression{}, err
+		}
+		return Operation(OpNot, expression), nil
+	}
+	return p.predicate()
+}
+
+func (p *textParser) predicate() (Expression, error) {
+	if p.peek().typ == tokenOpenParenthesis {
+		p.next()
+		expression, err := p.or()
+		if err != nil {
+			return Expression{}, err
+		}
+		if _, err := p.expect(tokenCloseParenthesis, "')'"); err != nil {
+			return Expression{}, err
+		}
+		return expression, nil
+	}
+
+	left, err := p.scalar()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	if t := p.peek(); t.typ == tokenOperator {
+		p.next()
+		right, err := p.scalar()
+		if err != nil {
+			return Expression{}, err
+		}
+		return Operation(t.value, left, right), nil
+	}
+
+	if p.keyword("IS") {
+		negated := p.keyword("NOT")
+		if !p.keyword("NULL") {
+			return Expression{}, fmt.Errorf("%w: expected NULL at position %d", ErrSyntax, p.peek().position)
+		}
+		return negate(Operation(OpIsNull, left), negated), nil
+	}
+
+	negated := p.keyword("NOT")
+	switch {
+	case p.keyword("LIKE"):
+		pattern, err := p.scalar()
+		if err != nil {
+			return Expression{}, err
+		}
+		return negate(Operation(OpLike, left, pattern), negated), nil
+	case p.keyword("BETWEEN"):
+		lower, err := p.scalar()
+		if err != nil {
+			return Expression{}, err
+		}
+		if !p.keyword("AND") {
+			return Expression{}, fmt.Errorf("%w: expected AND at position %d", ErrSyntax, p.peek().position)
+		}
+		upper, err := p.scalar()
+		if err != nil {
+			return Expression{}, err
+		}
+		return negate(Operation(OpBetween, left, lower, upper), negated), nil
+	case p.keyword("IN"):
+		list, err := p.list()
+		if err != nil {
+			return Expression{}, err
+		}
+		return negate(Operation(OpIn, left, list), negated), nil
+	}
+
+	if negated {
+		return Expression{}, fmt.Errorf("%w: expected LIKE, BETWEEN or IN at position %d", ErrSyntax, p.peek().position)
+	}
+
+	// boolean literals are valid predicates on their own
+	if left.kind == kindLiteral {
+		if _, isBool := left.Value.(bool); isBool {
+			return left, nil
+		}
+	}
+	return Expression{}, fmt.Errorf("%w: expected a comparison at position %d", ErrSyntax, p.peek().position)
+}
+
+func (p *textParser) list() (Expression, error) {
+	if _, err := p.expect(tokenOpenParenthesis, "'('"); err != nil {
+		return Expression{}, err
+	}
+
+	var entries []Expression
+	for {
+		entry, err := p.scalar()
+		if err != nil {
+			return Expression{}, err
+		}
+		entries = append(entries, entry)
+
+		if p.peek().typ != tokenComma {
+			break
+		}
+		p.next()
+	}
+
+	if _, err := p.expect(tokenCloseParenthesis, "')'"); err != nil {
+		return Expression{}, err
+	}
+	return List(entries...), nil
+}
+
+func (p *textParser) scalar() (Expression, error) {
+	t := p.next()
+	switch t.typ {
+	case tokenString:
+		return Literal(t.value), nil
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return Expression{}, fmt.Errorf("%w: invalid number '%s' at position %d", ErrSyntax, t.value, t.position)
+		}
+		return Literal(value), nil
+	case tokenIdentifier:
+		if t.quoted {
+			return Property(t.value), nil
+		}
+		switch strings.ToUpper(t.value) {
+		case "TRUE":
+			return Literal(true), nil
+		case "FALSE":
+			return Literal(false), nil
+		}
+		return Property(t.value), nil
+	default:
+		return Expression{}, fmt.Errorf("%w: expected a property or a literal at position %d", ErrSyntax, t.position)
+	}
+}
+
+func negate(expression Expression, negated bool) Expression {
+	if negated {
+		return Operation(OpNot, expression)
+	}
+	return expression
+}
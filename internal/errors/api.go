@@ -24,7 +24,7 @@ var ErrUnsupportedSpatialRelation = types.ServiceError{
 	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
 	Status: http.StatusBadRequest,
 	Title:  "Unsupported Spatial Relation",
-	Detail: "The selected spatial relation for the query is not supported",
+	Detail: "The selected spatial relation for the query is not supported. Supported relations: intersects, touches, crosses, disjoint, equals, covers, coveredBy, within, overlaps, contains, dwithin (requires distance). Alternatively, a DE-9IM pattern may be set using relate",
 }
 
 var ErrMissingParameter = types.ServiceError{
@@ -47,3 +47,136 @@ var ErrLayerPrivate = types.ServiceError{
 	Title:  "Forbidden Layer Accessed",
 	Detail: "The specified layer requires more access priviliges to be displayed.",
 }
+
+var ErrUnauthenticated = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.2",
+	Status: http.StatusUnauthorized,
+	Title:  "Authentication Required",
+	Detail: "The requested action requires a valid access token.",
+}
+
+var ErrMissingWritePermission = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.4",
+	Status: http.StatusForbidden,
+	Title:  "Missing Write Permission",
+	Detail: "The requested action requires write access to the service.",
+}
+
+var ErrInvalidLayerKey = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Invalid Layer Key",
+	Detail: "The layer key may only contain lowercase letters, digits and underscores and needs to start with a letter (max. 63 characters).",
+}
+
+var ErrLayerKeyExists = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.10",
+	Status: http.StatusConflict,
+	Title:  "Layer Key Already Used",
+	Detail: "The layer key clashes with an existing layer or table. Please choose another key",
+}
+
+var ErrUnknownCRS = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Unknown Coordinate Reference System",
+	Detail: "The coordinate reference system is not known to the database. Please use an EPSG code (e.g. 25832 or EPSG:25832) or its OGC URI",
+}
+
+var ErrInvalidShapefile = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Invalid Shapefile",
+	Detail: "The uploaded file is not a zipped shapefile or could not be read. Check the error field for more information",
+}
+
+var ErrUndetectableShapefileCRS = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Undetectable Coordinate Reference System",
+	Detail: "The coordinate reference system of the shapefile could not be detected. Please supply the EPSG code using the 'epsg' parameter",
+}
+
+var ErrUnknownShapefileField = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Unknown Shapefile Field",
+	Detail: "The selected key or name field does not exist in the shapefile",
+}
+
+var ErrUnsupportedOutputFormat = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Unsupported Output Format",
+	Detail: "The requested output format is not supported. Supported formats: json, geojson, geojsonseq, ndjson",
+}
+
+var ErrInvalidParameter = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Invalid Parameter Value",
+	Detail: "The request contains a parameter with an invalid value. Check the error field for more information",
+}
+
+var ErrInvalidBoundingBox = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Invalid Bounding Box",
+	Detail: "The bounding box needs to be supplied as 'minx,miny,maxx,maxy' with the minimal coordinates not exceeding the maximal coordinates",
+}
+
+var ErrInvalidGeometry = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Invalid Geometry",
+	Detail: "The supplied geometry is not a valid GeoJSON geometry",
+}
+
+var ErrInvalidFilter = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Invalid Filter",
+	Detail: "The filter is not a valid CQL2 expression or uses unsupported operators. Check the error field for more information",
+}
+
+var ErrUnknownFeature = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.5",
+	Status: http.StatusNotFound,
+	Title:  "Unknown Feature",
+	Detail: "The requested feature does not exist in the collection",
+}
+
+var ErrInvalidTile = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Invalid Tile",
+	Detail: "The requested tile does not exist in the Web Mercator tile matrix",
+}
+
+var ErrInvalidSchema = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Invalid Schema",
+	Detail: "The supplied schema is not a JSON Schema object",
+}
+
+var ErrMissingLayerTable = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.6.1",
+	Status: http.StatusInternalServerError,
+	Title:  "Missing Layer Table",
+	Detail: "The table containing the objects of a layer does not exist. Please contact the maintainers of the layer",
+}
+
+var ErrInvalidFeature = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.1",
+	Status: http.StatusBadRequest,
+	Title:  "Invalid Feature",
+	Detail: "The request body is not a valid GeoJSON Feature. Check the error field for more information",
+}
+
+var ErrObjectKeyExists = types.ServiceError{
+	Type:   "https://www.rfc-editor.org/rfc/rfc9110#section-15.5.10",
+	Status: http.StatusConflict,
+	Title:  "Object Key Already Used",
+	Detail: "The layer already contains an object with the key. Please choose another key",
+}
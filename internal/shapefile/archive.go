@@ -0,0 +1,165 @@
+// Package shapefile implements a reader for zipped ESRI shapefiles.
+//
+// Only the parts required for importing the shapes into the database are
+// supported. The geometries are always read as two-dimensional geometries
+// and the Z and M values of the shapes are dropped.
+package shapefile
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/twpayne/go-geom"
+)
+
+var (
+	ErrMissingShapeFile    = errors.New("archive does not contain a .shp file")
+	ErrMissingDatabaseFile = errors.New("archive does not contain a .dbf file")
+	ErrMultipleShapefiles  = errors.New("archive contains multiple shapefiles")
+	ErrFileTooLarge        = errors.New("archive contains a file exceeding the maximum size")
+)
+
+// MaxFileSize limits the uncompressed size of the files read from the archive
+// to prevent small archives from exhausting the memory.
+const MaxFileSize = 512 << 20
+
+// Feature is a single record of the shapefile consisting of the shape stored
+// in the .shp file and the attributes stored in the .dbf file.
+type Feature struct {
+	Geometry   geom.T
+	Attributes map[string]interface{}
+}
+
+// Archive contains the decoded contents of a zipped shapefile.
+type Archive struct {
+	// GeometryType contains the shape type declared in the header of the .shp
+	// file (e.g. Polygon)
+	GeometryType string
+
+	// Fields contains the names of the attributes in the order they are
+	// declared in the .dbf file
+	Fields []string
+
+	// Features contains the records of the shapefile
+	Features []Feature
+
+	// Projection contains the well-known text representation of the
+	// coordinate reference system stored in the .prj file
+	Projection string
+
+	// Encoding contains the character encoding declared in the .cpg file
+	Encoding string
+}
+
+// Read decodes a zipped shapefile. The archive needs to contain exactly one
+// .shp file and the matching .dbf file. The .prj and .cpg files are optional
+// while the .shx file is not required as the records are read sequentially.
+func Read(r io.ReaderAt, size int64) (*Archive, error) {
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File)
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() || strings.HasPrefix(path.Base(file.Name), ".") {
+			continue
+		}
+		extension := strings.ToLower(path.Ext(file.Name))
+		if _, exists := files[extension]; exists && extension == ".shp" {
+			return nil, ErrMultipleShapefiles
+		}
+		files[extension] = file
+	}
+
+	if files[".shp"] == nil {
+		return nil, ErrMissingShapeFile
+	}
+	if files[".dbf"] == nil {
+		return nil, ErrMissingDatabaseFile
+	}
+
+	var archive Archive
+	if files[".prj"] != nil {
+		contents, err := readFile(files[".prj"])
+		if err != nil {
+			return nil, err
+		}
+		archive.Projection = strings.TrimSpace(string(contents))
+	}
+
+	if files[".cpg"] != nil {
+		contents, err := readFile(files[".cpg"])
+		if err != nil {
+			return nil, err
+		}
+		archive.Encoding = strings.TrimSpace(string(contents))
+	}
+
+	shapeContents, err := readFile(files[".shp"])
+	if err != nil {
+		return nil, err
+	}
+
+	shapeType, geometries, err := decodeShapes(shapeContents)
+	if err != nil {
+		return nil, err
+	}
+	archive.GeometryType = shapeType.String()
+
+	databaseContents, err := readFile(files[".dbf"])
+	if err != nil {
+		return nil, err
+	}
+
+	fields, records, err := decodeDatabase(databaseContents, archive.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	archive.Fields = fields
+
+	if len(records) != len(geometries) {
+		return nil, errors.New("number of shapes and attribute records differ")
+	}
+
+	archive.Features = make([]Feature, 0, len(geometries))
+	for idx, geometry := range geometries {
+		if records[idx] == nil {
+			// the record has been marked as deleted
+			continue
+		}
+		archive.Features = append(archive.Features, Feature{
+			Geometry:   geometry,
+			Attributes: records[idx],
+		})
+	}
+
+	return &archive, nil
+}
+
+// readFile reads the uncompressed contents of the file. The size declared in
+// the archive is not trusted, so the contents are read through a limited
+// reader as well.
+func readFile(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	fd, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	contents, err := io.ReadAll(io.LimitReader(fd, MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(contents) > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+	return contents, nil
+}
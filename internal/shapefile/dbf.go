@@ -0,0 +1,180 @@
+package shapefile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	databaseHeaderLength = 32
+	fieldDescriptorLen   = 32
+	fieldTerminator      = 0x0D
+	deletedRecordMarker  = '*'
+)
+
+type field struct {
+	name     string
+	kind     byte
+	length   int
+	decimals int
+}
+
+// decodeDatabase reads the field names and the records of a dBASE file.
+// Deleted records are returned as nil maps to keep the records aligned with
+// the records of the .shp file.
+func decodeDatabase(contents []byte, codePage string) ([]string, []map[string]interface{}, error) {
+	if len(contents) < databaseHeaderLength {
+		return nil, nil, errors.New("dBASE header is truncated")
+	}
+
+	recordCount := int(binary.LittleEndian.Uint32(contents[4:8]))
+	headerLength := int(binary.LittleEndian.Uint16(contents[8:10]))
+	recordLength := int(binary.LittleEndian.Uint16(contents[10:12]))
+	if headerLength > len(contents) {
+		return nil, nil, errors.New("dBASE header is truncated")
+	}
+	if recordLength < 1 {
+		return nil, nil, errors.New("dBASE record length is invalid")
+	}
+
+	// the record count is read from the header and is therefore checked
+	// against the contents before allocating the records
+	if recordCount > (len(contents)-headerLength)/recordLength {
+		return nil, nil, errors.New("dBASE record is truncated")
+	}
+
+	decoder := textDecoder(codePage)
+
+	var fields []field
+	var fieldNames []string
+	for offset := databaseHeaderLength; offset+fieldDescriptorLen <= headerLength; offset += fieldDescriptorLen {
+		if contents[offset] == fieldTerminator {
+			break
+		}
+		descriptor := contents[offset : offset+fieldDescriptorLen]
+		name, _, _ := bytes.Cut(descriptor[0:11], []byte{0})
+		f := field{
+			name:     decoder(name),
+			kind:     descriptor[11],
+			length:   int(descriptor[16]),
+			decimals: int(descriptor[17]),
+		}
+		fields = append(fields, f)
+		fieldNames = append(fieldNames, f.name)
+	}
+
+	records := make([]map[string]interface{}, 0, recordCount)
+	for idx := range recordCount {
+		offset := headerLength + idx*recordLength
+		if offset+recordLength > len(contents) {
+			return nil, nil, errors.New("dBASE record is truncated")
+		}
+		record := contents[offset : offset+recordLength]
+		if record[0] == deletedRecordMarker {
+			records = append(records, nil)
+			continue
+		}
+
+		attributes := make(map[string]interface{}, len(fields))
+		position := 1
+		for _, f := range fields {
+			if position+f.length > len(record) {
+				return nil, nil, errors.New("dBASE record is shorter than its fields")
+			}
+			attributes[f.name] = f.decode(record[position:position+f.length], decoder)
+			position += f.length
+		}
+		records = append(records, attributes)
+	}
+
+	return fieldNames, records, nil
+}
+
+// decode converts the raw value of the field into a Go value. Empty values
+// are returned as nil.
+func (f field) decode(raw []byte, decoder func([]byte) string) interface{} {
+	value := strings.TrimSpace(decoder(raw))
+	if value == "" {
+		return nil
+	}
+
+	switch f.kind {
+	case 'N', 'F':
+		if f.decimals == 0 {
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return i
+			}
+		}
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+		// numeric fields are padded with asterisks if the value overflows
+		return nil
+	case 'L':
+		switch value {
+		case "T", "t", "Y", "y":
+			return true
+		case "F", "f", "N", "n":
+			return false
+		default:
+			return nil
+		}
+	case 'D':
+		// dates are stored as YYYYMMDD
+		if len(value) == len("YYYYMMDD") {
+			return value[0:4] + "-" + value[4:6] + "-" + value[6:8]
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// textDecoder returns a function decoding the raw strings of the dBASE file
+// according to the code page from the .cpg file. If no code page is set, the
+// values are used as-is if they are valid UTF-8 and are decoded as Windows-1252
+// otherwise, which is the most common encoding of files without code page.
+func textDecoder(codePage string) func([]byte) string {
+	var enc encoding.Encoding
+	normalizedCodePage := strings.NewReplacer("-", "", "_", "", " ", "").Replace(codePage)
+	switch strings.ToUpper(normalizedCodePage) {
+	case "UTF8", "65001":
+		return func(b []byte) string { return string(b) }
+	case "ISO88591", "88591", "LATIN1":
+		enc = charmap.ISO8859_1
+	case "ISO885915", "885915", "LATIN9":
+		enc = charmap.ISO8859_15
+	case "1252", "CP1252", "WINDOWS1252", "ANSI1252":
+		enc = charmap.Windows1252
+	case "437", "CP437":
+		enc = charmap.CodePage437
+	case "850", "CP850":
+		enc = charmap.CodePage850
+	default:
+		enc = charmap.Windows1252
+		if codePage == "" {
+			return func(b []byte) string {
+				if utf8.Valid(b) {
+					return string(b)
+				}
+				decoded, _ := enc.NewDecoder().Bytes(b)
+				return string(decoded)
+			}
+		}
+	}
+
+	return func(b []byte) string {
+		decoded, err := enc.NewDecoder().Bytes(b)
+		if err != nil {
+			return string(b)
+		}
+		return string(decoded)
+	}
+}
@@ -0,0 +1,213 @@
+package shapefile_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twpayne/go-geom"
+
+	"microservice/internal/shapefile"
+)
+
+// buildPolygonShapes creates the contents of a .shp file containing the
+// supplied polygon records. Each record consists of one or multiple rings.
+func buildPolygonShapes(records [][][][2]float64) []byte {
+	var body bytes.Buffer
+	for idx, rings := range records {
+		var content bytes.Buffer
+		var numPoints int
+		for _, ring := range rings {
+			numPoints += len(ring)
+		}
+		_ = binary.Write(&content, binary.LittleEndian, int32(shapefile.ShapePolygon))
+		_ = binary.Write(&content, binary.LittleEndian, [4]float64{})
+		_ = binary.Write(&content, binary.LittleEndian, int32(len(rings)))
+		_ = binary.Write(&content, binary.LittleEndian, int32(numPoints))
+		start := 0
+		for _, ring := range rings {
+			_ = binary.Write(&content, binary.LittleEndian, int32(start))
+			start += len(ring)
+		}
+		for _, ring := range rings {
+			for _, point := range ring {
+				_ = binary.Write(&content, binary.LittleEndian, math.Float64bits(point[0]))
+				_ = binary.Write(&content, binary.LittleEndian, math.Float64bits(point[1]))
+			}
+		}
+
+		_ = binary.Write(&body, binary.BigEndian, int32(idx+1))
+		_ = binary.Write(&body, binary.BigEndian, int32(content.Len()/2))
+		body.Write(content.Bytes())
+	}
+
+	header := make([]byte, 100)
+	binary.BigEndian.PutUint32(header[0:4], 9994)
+	binary.BigEndian.PutUint32(header[24:28], uint32((100+body.Len())/2))
+	binary.LittleEndian.PutUint32(header[28:32], 1000)
+	binary.LittleEndian.PutUint32(header[32:36], uint32(shapefile.ShapePolygon))
+	return append(header, body.Bytes()...)
+}
+
+// buildDatabase creates the contents of a .dbf file with a character field
+// "NAME" and a numeric field "DEPTH".
+func buildDatabase(names [][]byte, depths []string, deleted []bool) []byte {
+	const nameLength, depthLength = 20, 6
+	var buf bytes.Buffer
+	header := make([]byte, 32)
+	header[0] = 0x03
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(names)))
+	binary.LittleEndian.PutUint16(header[8:10], 32+2*32+1)
+	binary.LittleEndian.PutUint16(header[10:12], 1+nameLength+depthLength)
+	buf.Write(header)
+
+	nameField := make([]byte, 32)
+	copy(nameField, "NAME")
+	nameField[11] = 'C'
+	nameField[16] = nameLength
+	buf.Write(nameField)
+
+	depthField := make([]byte, 32)
+	copy(depthField, "DEPTH")
+	depthField[11] = 'N'
+	depthField[16] = depthLength
+	buf.Write(depthField)
+	buf.WriteByte(0x0D)
+
+	for idx, name := range names {
+		if deleted[idx] {
+			buf.WriteByte('*')
+		} else {
+			buf.WriteByte(' ')
+		}
+		buf.Write(append(name, bytes.Repeat([]byte{' '}, nameLength-len(name))...))
+		buf.Write(append(bytes.Repeat([]byte{' '}, depthLength-len(depths[idx])), depths[idx]...))
+	}
+	buf.WriteByte(0x1A)
+	return buf.Bytes()
+}
+
+func buildArchive(t *testing.T, files map[string][]byte) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, contents := range files {
+		fd, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fd.Write(contents)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func Test_Read(t *testing.T) {
+	outer := [][2]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}
+	hole := [][2]float64{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}}
+	second := [][2]float64{{20, 20}, {20, 30}, {30, 30}, {30, 20}, {20, 20}}
+
+	shapes := buildPolygonShapes([][][][2]float64{
+		{outer, hole},
+		{second},
+		{second},
+	})
+	database := buildDatabase(
+		[][]byte{[]byte("Oldenburg"), {'M', 0xFC, 'n', 's', 't', 'e', 'r'}, []byte("Deleted")},
+		[]string{"52", "", "1"},
+		[]bool{false, false, true},
+	)
+
+	reader := buildArchive(t, map[string][]byte{
+		"data/wells.shp": shapes,
+		"data/wells.dbf": database,
+		"data/wells.prj": []byte(`PROJCS["ETRS_1989_UTM_Zone_32N"]`),
+		"data/wells.cpg": []byte("1252"),
+	})
+
+	archive, err := shapefile.Read(reader, reader.Size())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "Polygon", archive.GeometryType)
+	assert.Equal(t, []string{"NAME", "DEPTH"}, archive.Fields)
+	assert.Equal(t, `PROJCS["ETRS_1989_UTM_Zone_32N"]`, archive.Projection)
+	assert.Len(t, archive.Features, 2)
+
+	first, isMultiPolygon := archive.Features[0].Geometry.(*geom.MultiPolygon)
+	if assert.True(t, isMultiPolygon) {
+		assert.Equal(t, 1, first.NumPolygons())
+		assert.Equal(t, 2, first.Polygon(0).NumLinearRings())
+	}
+
+	assert.Equal(t, "Oldenburg", archive.Features[0].Attributes["NAME"])
+	assert.Equal(t, int64(52), archive.Features[0].Attributes["DEPTH"])
+	assert.Equal(t, "Münster", archive.Features[1].Attributes["NAME"])
+	assert.Nil(t, archive.Features[1].Attributes["DEPTH"])
+}
+
+func Test_Read_MissingDatabase(t *testing.T) {
+	reader := buildArchive(t, map[string][]byte{
+		"wells.shp": buildPolygonShapes(nil),
+	})
+
+	_, err := shapefile.Read(reader, reader.Size())
+	assert.ErrorIs(t, err, shapefile.ErrMissingDatabaseFile)
+}
+
+func Test_Read_InvalidRecordLength(t *testing.T) {
+	database := buildDatabase([][]byte{[]byte("Oldenburg")}, []string{"52"}, []bool{false})
+	binary.LittleEndian.PutUint16(database[10:12], 0)
+
+	reader := buildArchive(t, map[string][]byte{
+		"wells.shp": buildPolygonShapes(nil),
+		"wells.dbf": database,
+	})
+
+	_, err := shapefile.Read(reader, reader.Size())
+	assert.Error(t, err)
+}
+
+func Test_Read_TruncatedRecords(t *testing.T) {
+	database := buildDatabase([][]byte{[]byte("Oldenburg")}, []string{"52"}, []bool{false})
+	binary.LittleEndian.PutUint32(database[4:8], math.MaxUint32)
+
+	reader := buildArchive(t, map[string][]byte{
+		"wells.shp": buildPolygonShapes(nil),
+		"wells.dbf": database,
+	})
+
+	_, err := shapefile.Read(reader, reader.Size())
+	assert.Error(t, err)
+}
+
+func Test_Read_FileTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	fd, err := writer.Create("wells.shp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := make([]byte, 1<<20)
+	for written := 0; written <= shapefile.MaxFileSize; written += len(chunk) {
+		_, _ = fd.Write(chunk)
+	}
+	fd, err = writer.Create("wells.dbf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fd.Write(buildDatabase(nil, nil, nil))
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := bytes.NewReader(buf.Bytes())
+	_, err = shapefile.Read(reader, reader.Size())
+	assert.ErrorIs(t, err, shapefile.ErrFileTooLarge)
+}
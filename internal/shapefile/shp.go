@@ -0,0 +1,272 @@
+package shapefile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/twpayne/go-geom"
+)
+
+// ShapeType is the type of shape as declared in the .shp file.
+type ShapeType int32
+
+const (
+	ShapeNull        ShapeType = 0
+	ShapePoint       ShapeType = 1
+	ShapePolyLine    ShapeType = 3
+	ShapePolygon     ShapeType = 5
+	ShapeMultiPoint  ShapeType = 8
+	ShapePointZ      ShapeType = 11
+	ShapePolyLineZ   ShapeType = 13
+	ShapePolygonZ    ShapeType = 15
+	ShapeMultiPointZ ShapeType = 18
+	ShapePointM      ShapeType = 21
+	ShapePolyLineM   ShapeType = 23
+	ShapePolygonM    ShapeType = 25
+	ShapeMultiPointM ShapeType = 28
+)
+
+// base returns the two-dimensional shape type for shape types with Z or M
+// values.
+func (t ShapeType) base() ShapeType {
+	switch t {
+	case ShapePointZ, ShapePointM:
+		return ShapePoint
+	case ShapePolyLineZ, ShapePolyLineM:
+		return ShapePolyLine
+	case ShapePolygonZ, ShapePolygonM:
+		return ShapePolygon
+	case ShapeMultiPointZ, ShapeMultiPointM:
+		return ShapeMultiPoint
+	default:
+		return t
+	}
+}
+
+func (t ShapeType) String() string {
+	switch t.base() {
+	case ShapeNull:
+		return "Null"
+	case ShapePoint:
+		return "Point"
+	case ShapePolyLine:
+		return "PolyLine"
+	case ShapePolygon:
+		return "Polygon"
+	case ShapeMultiPoint:
+		return "MultiPoint"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int32(t))
+	}
+}
+
+const (
+	shapeFileCode     = 9994
+	shapeHeaderLength = 100
+	recordHeaderLen   = 8
+	boundingBoxLength = 32
+	pointLength       = 16
+)
+
+var errTruncatedShape = errors.New("shape record is truncated")
+
+// decodeShapes reads all records of a .shp file and converts them into
+// geometries. Null shapes are returned as nil geometries to keep the records
+// aligned with the records of the .dbf file.
+func decodeShapes(contents []byte) (ShapeType, []geom.T, error) {
+	if len(contents) < shapeHeaderLength {
+		return 0, nil, errors.New("shape file header is truncated")
+	}
+	if binary.BigEndian.Uint32(contents[0:4]) != shapeFileCode {
+		return 0, nil, errors.New("invalid shape file code")
+	}
+	shapeType := ShapeType(binary.LittleEndian.Uint32(contents[32:36]))
+
+	var geometries []geom.T
+	offset := shapeHeaderLength
+	for offset+recordHeaderLen <= len(contents) {
+		// the content length is given in 16-bit words
+		contentLength := int(binary.BigEndian.Uint32(contents[offset+4:offset+8])) * 2
+		offset += recordHeaderLen
+		if offset+contentLength > len(contents) {
+			return 0, nil, errTruncatedShape
+		}
+
+		record := contents[offset : offset+contentLength]
+		offset += contentLength
+		if len(record) >= 4 && ShapeType(binary.LittleEndian.Uint32(record[0:4])) == ShapeNull {
+			geometries = append(geometries, nil)
+			continue
+		}
+
+		geometry, err := decodeShape(record)
+		if err != nil {
+			return 0, nil, fmt.Errorf("record %d: %w", len(geometries)+1, err)
+		}
+		geometries = append(geometries, geometry)
+	}
+
+	return shapeType, geometries, nil
+}
+
+// decodeShape converts the contents of a single record into a geometry.
+// Lines are always returned as multi line strings and polygons as multi
+// polygons as the shapefile does not differentiate between them.
+func decodeShape(record []byte) (geom.T, error) {
+	if len(record) < 4 {
+		return nil, errTruncatedShape
+	}
+	shapeType := ShapeType(binary.LittleEndian.Uint32(record[0:4]))
+	record = record[4:]
+
+	switch shapeType.base() {
+	case ShapePoint:
+		if len(record) < pointLength {
+			return nil, errTruncatedShape
+		}
+		return geom.NewPoint(geom.XY).MustSetCoords(readPoint(record)), nil
+	case ShapeMultiPoint:
+		if len(record) < boundingBoxLength+4 {
+			return nil, errTruncatedShape
+		}
+		numPoints := int(binary.LittleEndian.Uint32(record[boundingBoxLength:]))
+		points, err := readPoints(record[boundingBoxLength+4:], numPoints)
+		if err != nil {
+			return nil, err
+		}
+		return geom.NewMultiPoint(geom.XY).SetCoords(points)
+	case ShapePolyLine:
+		parts, err := readParts(record)
+		if err != nil {
+			return nil, err
+		}
+		return geom.NewMultiLineString(geom.XY).SetCoords(parts)
+	case ShapePolygon:
+		parts, err := readParts(record)
+		if err != nil {
+			return nil, err
+		}
+		return geom.NewMultiPolygon(geom.XY).SetCoords(assembleRings(parts))
+	default:
+		return nil, fmt.Errorf("unsupported shape type %d", int32(shapeType))
+	}
+}
+
+func readPoint(b []byte) geom.Coord {
+	return geom.Coord{
+		math.Float64frombits(binary.LittleEndian.Uint64(b[0:8])),
+		math.Float64frombits(binary.LittleEndian.Uint64(b[8:16])),
+	}
+}
+
+func readPoints(b []byte, count int) ([]geom.Coord, error) {
+	if count < 0 || len(b) < count*pointLength {
+		return nil, errTruncatedShape
+	}
+	points := make([]geom.Coord, count)
+	for idx := range count {
+		points[idx] = readPoint(b[idx*pointLength:])
+	}
+	return points, nil
+}
+
+// readParts reads the parts of a PolyLine or Polygon record.
+func readParts(record []byte) ([][]geom.Coord, error) {
+	if len(record) < boundingBoxLength+8 {
+		return nil, errTruncatedShape
+	}
+	numParts := int(binary.LittleEndian.Uint32(record[boundingBoxLength:]))
+	numPoints := int(binary.LittleEndian.Uint32(record[boundingBoxLength+4:]))
+	record = record[boundingBoxLength+8:]
+	if numParts < 0 || len(record) < numParts*4 {
+		return nil, errTruncatedShape
+	}
+
+	starts := make([]int, numParts)
+	for idx := range numParts {
+		starts[idx] = int(binary.LittleEndian.Uint32(record[idx*4:]))
+	}
+
+	points, err := readPoints(record[numParts*4:], numPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([][]geom.Coord, numParts)
+	for idx, start := range starts {
+		end := numPoints
+		if idx+1 < numParts {
+			end = starts[idx+1]
+		}
+		if start < 0 || start > end || end > numPoints {
+			return nil, errors.New("invalid part index")
+		}
+		parts[idx] = points[start:end]
+	}
+	return parts, nil
+}
+
+// assembleRings groups the rings of a polygon record into polygons.
+// Outer rings are stored in clockwise order while holes are stored in
+// counterclockwise order. Each hole is assigned to the outer ring containing
+// it.
+func assembleRings(rings [][]geom.Coord) [][][]geom.Coord {
+	var polygons [][][]geom.Coord
+	var holes [][]geom.Coord
+	for _, ring := range rings {
+		if signedArea(ring) <= 0 {
+			polygons = append(polygons, [][]geom.Coord{ring})
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+
+	// without any clockwise ring the orientation can't be trusted and every
+	// ring is handled as a separate polygon
+	if len(polygons) == 0 {
+		for _, ring := range holes {
+			polygons = append(polygons, [][]geom.Coord{ring})
+		}
+		return polygons
+	}
+
+	for _, hole := range holes {
+		target := len(polygons) - 1
+		for idx, polygon := range polygons {
+			if len(hole) > 0 && containsPoint(polygon[0], hole[0]) {
+				target = idx
+				break
+			}
+		}
+		polygons[target] = append(polygons[target], hole)
+	}
+	return polygons
+}
+
+// signedArea calculates the area of the ring using the shoelace formula.
+// The area is negative for rings in clockwise order.
+func signedArea(ring []geom.Coord) float64 {
+	var area float64
+	for idx := range ring {
+		next := ring[(idx+1)%len(ring)]
+		area += ring[idx].X()*next.Y() - next.X()*ring[idx].Y()
+	}
+	return area / 2
+}
+
+// containsPoint checks if the point is located inside the ring using the
+// ray casting algorithm.
+func containsPoint(ring []geom.Coord, point geom.Coord) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i].X(), ring[i].Y()
+		xj, yj := ring[j].X(), ring[j].Y()
+		if (yi > point.Y()) != (yj > point.Y()) &&
+			point.X() < (xj-xi)*(point.Y()-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	_ "embed"
 	"os"
 	"os/signal"
 
@@ -17,6 +18,12 @@ import (
 	"microservice/routes"
 )
 
+// openapiDocument contains the OpenAPI definition of the service which is
+// served by the OGC API - Features endpoints.
+//
+//go:embed openapi.yaml
+var openapiDocument []byte
+
 // the main function bootstraps the http server and handlers used for this
 // microservice.
 func main() {
@@ -41,13 +48,54 @@ func main() {
 	r.Use(middlewares.EnablePrivateLayers)
 
 	r.GET("/", routes.LayerOverview)
+	r.POST("/", middlewares.RequireWriteAccess, routes.CreateLayer)
 	r.GET("/:layerID", middlewares.ResolveLayer, routes.LayerInformation)
+	r.GET("/:layerID/statistics", middlewares.ResolveLayer, routes.LayerStatistics)
+	r.GET("/:layerID/schema", middlewares.ResolveLayer, routes.LayerSchema)
+	r.PUT("/:layerID/schema", middlewares.RequireWriteAccess, middlewares.ResolveLayer, routes.PinLayerSchema)
+	r.DELETE("/:layerID/schema", middlewares.RequireWriteAccess, middlewares.ResolveLayer, routes.UnpinLayerSchema)
 	r.GET("/identify", routes.IdentifyObject)
+	r.GET("/identify/point", routes.IdentifyPoint)
+	r.GET("/search", routes.Search)
+
+	r.POST("/shapefile", middlewares.RequireWriteAccess, routes.IntrospectShapefile)
+	r.POST("/shapefile/import", middlewares.RequireWriteAccess, routes.ImportShapefile)
 
 	content := r.Group("/content", middlewares.ResolveLayer)
 	{
 		content.GET("/:layerID", routes.LayerContents)
 		content.GET("/:layerID/filtered", routes.FilteredLayerContents)
+		content.GET("/:layerID/id/:id", routes.LayerObjectByID)
+		content.GET("/:layerID/:key", routes.LayerObject)
+		content.GET("/:layerID/:key/children", routes.LayerChildren)
+		content.POST("/:layerID/-/query", routes.QueryLayerContents)
+		content.POST("/:layerID/-/bulk", middlewares.RequireWriteAccess, routes.BulkObjects)
+		content.POST("/:layerID/:key", middlewares.RequireWriteAccess, routes.CreateObject)
+		content.PUT("/:layerID/:key", middlewares.RequireWriteAccess, routes.ReplaceObject)
+		content.PATCH("/:layerID/:key", middlewares.RequireWriteAccess, routes.UpdateObject)
+		content.DELETE("/:layerID/:key", middlewares.RequireWriteAccess, routes.DeleteObject)
+	}
+
+	tiles := r.Group("/tiles")
+	{
+		tiles.GET("/:layerID/:z/:x/:y", middlewares.ResolveLayer, routes.LayerTile)
+		tiles.GET("/:layerID/tilejson.json", middlewares.ResolveLayer, routes.LayerTileJSON)
+		tiles.GET("/composite/:z/:x/:y", routes.CompositeTile)
+		tiles.GET("/composite/tilejson.json", routes.CompositeTileJSON)
+	}
+
+	routes.OpenAPIDocument = openapiDocument
+	ogc := r.Group("/ogc")
+	{
+		ogc.GET("", routes.OGCLandingPage)
+		ogc.GET("/conformance", routes.OGCConformance)
+		ogc.GET("/api", routes.OGCAPIDefinition)
+		ogc.GET("/collections", routes.OGCCollections)
+
+		collections := ogc.Group("/collections/:layerID", middlewares.ResolveLayer)
+		collections.GET("", routes.OGCCollection)
+		collections.GET("/items", routes.OGCItems)
+		collections.GET("/items/:featureId", routes.OGCItem)
 	}
 
 	l.Info().Msg("finished service configuration")
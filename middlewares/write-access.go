@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wisdom-oss/common-go/v3/middleware/gin/jwt"
+
+	"microservice/internal"
+	apiErrors "microservice/internal/errors"
+)
+
+// RequireWriteAccess only allows the request to continue if the access token
+// has been validated and carries the write permission of the service.
+// Administrators are always allowed to write.
+func RequireWriteAccess(c *gin.Context) {
+	tokenValid := c.GetBool(jwt.KeyTokenValidated)
+	isAdmin := c.GetBool(jwt.KeyAdministrator)
+	permissions := c.GetStringSlice(jwt.KeyTokenPermissions)
+
+	if isAdmin || (tokenValid && slices.Contains(permissions, internal.ServiceName+":write")) {
+		c.Next()
+		return
+	}
+
+	c.Abort()
+	if !tokenValid {
+		apiErrors.ErrUnauthenticated.Emit(c)
+		return
+	}
+	apiErrors.ErrMissingWritePermission.Emit(c)
+}
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS geodata.layers
+ADD COLUMN IF NOT EXISTS "schema" jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS geodata.layers
+DROP COLUMN "schema"
+-- +goose StatementEnd
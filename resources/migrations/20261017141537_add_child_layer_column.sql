@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS geodata.layers
+ADD COLUMN IF NOT EXISTS child_layer uuid REFERENCES geodata.layers (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS geodata.layers
+DROP COLUMN child_layer
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE IF EXISTS geodata.layers
+ADD COLUMN IF NOT EXISTS search_properties text[];
+
+-- the indexes are created without a name as the names derived from long
+-- layer keys would be truncated to the same identifier
+DO $$
+DECLARE
+    layer_table text;
+BEGIN
+    FOR layer_table IN SELECT "table" FROM geodata.layers LOOP
+        IF to_regclass(format('geodata.%I', layer_table)) IS NULL THEN
+            CONTINUE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT FROM pg_indexes
+            WHERE schemaname = 'geodata' AND tablename = layer_table AND indexdef LIKE '%gin_trgm_ops%'
+        ) THEN
+            EXECUTE format('CREATE INDEX ON geodata.%I USING gin (name gin_trgm_ops)', layer_table);
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT FROM pg_indexes
+            WHERE schemaname = 'geodata' AND tablename = layer_table AND indexdef LIKE '%text_pattern_ops%'
+        ) THEN
+            EXECUTE format('CREATE INDEX ON geodata.%I (key text_pattern_ops)', layer_table);
+        END IF;
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DO $$
+DECLARE
+    search_index text;
+BEGIN
+    FOR search_index IN
+        SELECT
+            indexname
+        FROM
+            pg_indexes
+            JOIN geodata.layers ON tablename = layers."table"
+        WHERE
+            schemaname = 'geodata'
+            AND (indexdef LIKE '%gin_trgm_ops%' OR indexdef LIKE '%text_pattern_ops%')
+    LOOP
+        EXECUTE format('DROP INDEX IF EXISTS geodata.%I', search_index);
+    END LOOP;
+END $$;
+
+ALTER TABLE IF EXISTS geodata.layers
+DROP COLUMN search_properties;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+DECLARE
+    layer_table text;
+    has_duplicates boolean;
+BEGIN
+    FOR layer_table IN SELECT "table" FROM geodata.layers LOOP
+        IF to_regclass(format('geodata.%I', layer_table)) IS NULL THEN
+            CONTINUE;
+        END IF;
+
+        IF EXISTS (
+            SELECT
+            FROM
+                pg_constraint
+            WHERE
+                conrelid = format('geodata.%I', layer_table)::regclass
+                AND contype = 'u'
+                AND conkey = ARRAY[(
+                    SELECT attnum FROM pg_attribute
+                    WHERE attrelid = format('geodata.%I', layer_table)::regclass AND attname = 'key'
+                )]
+        ) THEN
+            CONTINUE;
+        END IF;
+
+        EXECUTE format('SELECT EXISTS (SELECT FROM geodata.%I GROUP BY key HAVING count(*) > 1)', layer_table)
+        INTO has_duplicates;
+
+        -- tables already containing duplicate keys need to be cleaned up
+        -- manually as the migration would fail otherwise
+        IF has_duplicates THEN
+            RAISE WARNING 'layer table geodata.% contains duplicate keys. the unique constraint has not been added', layer_table;
+            CONTINUE;
+        END IF;
+
+        EXECUTE format('ALTER TABLE geodata.%I ADD UNIQUE (key)', layer_table);
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DO $$
+DECLARE
+    key_constraint record;
+BEGIN
+    FOR key_constraint IN
+        SELECT
+            conrelid::regclass AS layer_table,
+            conname
+        FROM
+            pg_constraint
+            JOIN geodata.layers ON conrelid = to_regclass(format('geodata.%I', layers."table"))
+        WHERE
+            contype = 'u'
+    LOOP
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', key_constraint.layer_table, key_constraint.conname);
+    END LOOP;
+END $$;
+-- +goose StatementEnd
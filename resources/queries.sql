@@ -26,48 +26,432 @@ WHERE
 -- name: get-layer-contents
 SELECT
     id,
-    st_transform (geometry, 4326) AS geometry,
+    %[2]s AS geometry,
     key,
-    name,
-    additional_properties
+    %[3]s AS name,
+    %[4]s AS additional_properties
 FROM
-    geodata."%s";
+    geodata."%[1]s";
 
--- name: get-layer-object-by-key
+-- name: count-layer-contents
 SELECT
-    id,
-    st_transform (geometry, 4326) AS geometry,
-    key,
-    name,
-    additional_properties
+    count(*)
 FROM
-    geodata."%s"
-WHERE
-    key = $1::text;
+    geodata."%s";
 
 -- name: crate-layer-definition
 INSERT INTO
-    geodata.layers (name, description, "table", crs, attribution)
+    geodata.layers (name, description, "table", crs, attribution, private, child_layer, search_properties)
 VALUES
-    ($1, $2, $3, $4, $5)
+    ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING
     id,
     name,
     description,
     "table",
     crs,
-    attribution;
+    attribution,
+    private,
+    child_layer,
+    search_properties;
 
 -- name: create-layer-table
 CREATE TABLE
     geodata.%s (
         id bigserial PRIMARY KEY NOT NULL,
         geometry geometry NOT NULL,
-        key text NOT NULL,
+        key text NOT NULL UNIQUE,
         name text NOT NULL,
         additional_properties jsonb
     );
 
 -- name: update-geometry-srid
 SELECT
-    UpdateGeometrySRID ('geodata', $1, 'geometry', $2);
\ No newline at end of file
+    UpdateGeometrySRID ('geodata', $1, 'geometry', $2);
+
+-- name: create-layer-spatial-index
+CREATE INDEX ON geodata.%s USING gist (geometry);
+
+-- name: create-layer-search-indexes
+CREATE INDEX ON geodata.%[1]s USING gin (name gin_trgm_ops);
+
+CREATE INDEX ON geodata.%[1]s (key text_pattern_ops);
+
+-- name: create-layer-property-index
+CREATE INDEX ON geodata.%[1]s USING gin (%[2]s gin_trgm_ops);
+
+-- name: layer-table-exists
+SELECT
+    EXISTS (
+        SELECT
+            1
+        FROM
+            information_schema.tables
+        WHERE
+            table_schema = 'geodata'
+            AND table_name = $1
+    )
+    OR EXISTS (
+        SELECT
+            1
+        FROM
+            geodata.layers
+        WHERE
+            "table" = $1
+    );
+
+-- name: crs-exists
+SELECT
+    EXISTS (
+        SELECT
+            1
+        FROM
+            spatial_ref_sys
+        WHERE
+            srid = $1
+    );
+
+-- name: get-crs-by-name
+SELECT
+    srid
+FROM
+    spatial_ref_sys
+WHERE
+    auth_name = 'EPSG'
+    AND regexp_replace(lower(split_part(srtext, '"', 2)), '[^a-z0-9]', '', 'g') = $1
+ORDER BY
+    srid
+LIMIT
+    1;
+
+-- name: get-crs-proj4
+SELECT
+    coalesce(proj4text, '')
+FROM
+    spatial_ref_sys
+WHERE
+    srid = $1;
+
+-- name: insert-layer-object
+INSERT INTO
+    geodata."%s" (geometry, key, name, additional_properties)
+VALUES
+    (
+        st_transform (st_setsrid ($1::geometry, $2), $3),
+        $4,
+        $5,
+        $6
+    );
+
+-- name: replace-layer-object
+UPDATE geodata."%s"
+SET
+    geometry = st_transform (st_setsrid ($1::geometry, $2), $3),
+    key = $4,
+    name = $5,
+    additional_properties = $6
+WHERE
+    key = $7::text;
+
+-- name: patch-layer-object
+UPDATE geodata."%s"
+SET
+    geometry = coalesce(st_transform (st_setsrid ($1::geometry, $2), $3), geometry),
+    key = $4,
+    name = coalesce($5, name),
+    additional_properties = (
+        coalesce(
+            CASE WHEN jsonb_typeof (additional_properties) = 'object' THEN
+                additional_properties
+            END,
+            '{}'::jsonb
+        ) || $6::jsonb
+    ) - $7::text[]
+WHERE
+    key = $8::text;
+
+-- name: delete-layer-object
+DELETE FROM geodata."%s"
+WHERE
+    key = $1::text;
+
+-- name: layer-object-exists
+SELECT
+    EXISTS (
+        SELECT
+        FROM
+            geodata."%s"
+        WHERE
+            key = $1::text
+    );
+
+-- name: create-bulk-staging-table
+CREATE TEMPORARY TABLE bulk_objects (
+    position integer NOT NULL,
+    geometry geometry,
+    key text,
+    name text NOT NULL,
+    additional_properties jsonb,
+    reason text
+) ON COMMIT DROP;
+
+-- name: lock-layer-table
+LOCK TABLE geodata."%s" IN SHARE ROW EXCLUSIVE MODE;
+
+-- name: reject-invalid-bulk-geometries
+UPDATE bulk_objects
+SET
+    reason = 'invalid geometry: ' || ST_IsValidReason (geometry)
+WHERE
+    reason IS NULL
+    AND NOT ST_IsValid (geometry);
+
+-- name: reject-existing-bulk-keys
+UPDATE bulk_objects
+SET
+    reason = 'an object with the key already exists'
+WHERE
+    reason IS NULL
+    AND EXISTS (
+        SELECT
+        FROM
+            geodata."%s" AS objects
+        WHERE
+            objects.key = bulk_objects.key
+    );
+
+-- name: delete-missing-bulk-objects
+DELETE FROM geodata."%s" AS objects
+WHERE
+    NOT EXISTS (
+        SELECT
+        FROM
+            bulk_objects
+        WHERE
+            bulk_objects.key = objects.key
+    );
+
+-- name: update-bulk-objects
+UPDATE geodata."%s" AS objects
+SET
+    geometry = st_transform (st_setsrid (bulk_objects.geometry, $1), $2),
+    name = bulk_objects.name,
+    additional_properties = bulk_objects.additional_properties
+FROM
+    bulk_objects
+WHERE
+    bulk_objects.reason IS NULL
+    AND objects.key = bulk_objects.key;
+
+-- name: insert-bulk-objects
+INSERT INTO
+    geodata."%[1]s" (geometry, key, name, additional_properties)
+SELECT
+    st_transform (st_setsrid (geometry, $1), $2),
+    key,
+    name,
+    additional_properties
+FROM
+    bulk_objects
+WHERE
+    reason IS NULL
+    AND NOT EXISTS (
+        SELECT
+        FROM
+            geodata."%[1]s" AS objects
+        WHERE
+            objects.key = bulk_objects.key
+    )
+ORDER BY
+    position;
+
+-- name: get-rejected-bulk-objects
+SELECT
+    position,
+    key,
+    reason
+FROM
+    bulk_objects
+WHERE
+    reason IS NOT NULL
+ORDER BY
+    position;
+
+-- name: validate-geometry
+SELECT
+    valid,
+    reason
+FROM
+    ST_IsValidDetail ($1::geometry);
+
+-- name: get-layer-extent
+SELECT
+    st_xmin (extent),
+    st_ymin (extent),
+    st_xmax (extent),
+    st_ymax (extent)
+FROM (
+    SELECT
+        st_extent (st_transform (geometry, 4326)) AS extent
+    FROM
+        geodata."%s") AS layer_extent;
+
+-- name: get-layer-statistics
+SELECT
+    feature_count,
+    unnamed_count,
+    st_xmin (native_extent),
+    st_ymin (native_extent),
+    st_xmax (native_extent),
+    st_ymax (native_extent),
+    st_xmin (extent),
+    st_ymin (extent),
+    st_xmax (extent),
+    st_ymax (extent)
+FROM (
+    SELECT
+        count(*) AS feature_count,
+        count(*) FILTER (WHERE name IS NULL) AS unnamed_count,
+        st_extent (geometry) AS native_extent,
+        st_extent (st_transform (geometry, 4326)) AS extent
+    FROM
+        geodata."%s") AS layer_statistics;
+
+-- name: get-layer-geometry-types
+SELECT
+    geometrytype (geometry) AS geometry_type,
+    count(*)
+FROM
+    geodata."%s"
+WHERE
+    geometry IS NOT NULL
+GROUP BY
+    geometry_type;
+
+-- name: get-layer-attribute-counts
+SELECT
+    property.key,
+    count(*)
+FROM
+    geodata."%s"
+    CROSS JOIN LATERAL jsonb_each (
+        CASE WHEN jsonb_typeof (additional_properties) = 'object' THEN
+            additional_properties
+        END) AS property
+WHERE
+    property.value <> 'null'::jsonb
+GROUP BY
+    property.key;
+
+-- name: infer-layer-schema
+SELECT
+    property.key,
+    jsonb_typeof (property.value) AS type,
+    count(*),
+    count(DISTINCT property.value),
+    CASE WHEN count(DISTINCT property.value) <= $1 THEN
+        jsonb_agg(DISTINCT property.value)
+    END
+FROM
+    geodata."%s"
+    CROSS JOIN LATERAL jsonb_each (
+        CASE WHEN jsonb_typeof (additional_properties) = 'object' THEN
+            additional_properties
+        END) AS property
+GROUP BY
+    property.key,
+    type;
+
+-- name: pin-layer-schema
+UPDATE
+    geodata.layers
+SET
+    schema = $2
+WHERE
+    id = $1;
+
+-- name: get-missing-layer-tables
+SELECT
+    layer_table
+FROM
+    unnest($1::text[]) AS layer_table
+WHERE
+    to_regclass (format('geodata.%I', layer_table)) IS NULL;
+
+-- name: get-layer-tile
+WITH
+    bounds AS (
+        SELECT
+            st_tileenvelope ($1, $2, $3) AS envelope
+    ),
+    tile AS (
+        SELECT
+            objects.id,
+            st_asmvtgeom (st_transform (objects.geometry, 3857), bounds.envelope) AS geometry,
+            objects.key,
+            objects.name,
+            (
+                SELECT
+                    jsonb_object_agg(properties.key, properties.value)
+                FROM
+                    jsonb_each(objects.additional_properties) AS properties
+                WHERE
+                    properties.key = ANY ($5::text[])
+            ) AS additional_properties
+        FROM
+            geodata."%s" AS objects,
+            bounds
+        WHERE
+            objects.geometry && st_transform (bounds.envelope, %d)
+    )
+SELECT
+    st_asmvt (tile, $4, 4096, 'geometry', 'id')
+FROM
+    tile
+WHERE
+    geometry IS NOT NULL;
+
+-- name: get-layer-property-types
+SELECT
+    properties.key,
+    array_agg(DISTINCT jsonb_typeof(properties.value)) AS types
+FROM
+    geodata."%s" AS objects,
+    jsonb_each(objects.additional_properties) AS properties
+GROUP BY
+    properties.key;
+
+-- name: search-layer-objects
+SELECT
+    %[2]s::text AS layer,
+    key,
+    name,
+    CASE
+        WHEN box IS NULL THEN NULL
+        ELSE ARRAY[st_xmin (box), st_ymin (box), st_xmax (box), st_ymax (box)]
+    END AS bbox,
+    score
+FROM
+    (
+        SELECT
+            key,
+            name,
+            box2d (ST_Transform (geometry, 4326)) AS box,
+            greatest (
+                coalesce(word_similarity ($1, name), 0),
+                CASE
+                    WHEN key LIKE $3 THEN 1
+                    ELSE 0
+                END %[3]s
+            ) AS score
+        FROM
+            geodata."%[1]s"
+        WHERE
+            name ILIKE $2
+            OR $1 <%% name
+            OR key LIKE $3 %[4]s
+        ORDER BY
+            score DESC
+        LIMIT
+            $4
+    ) AS results
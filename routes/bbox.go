@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// boundingBoxFilter contains the query parameters restricting the objects to
+// a bounding box.
+type boundingBoxFilter struct {
+	BBox string `form:"bbox"`
+	CRS  string `form:"bbox-crs"`
+}
+
+// parseBoundingBox parses a bounding box in the "minx,miny,maxx,maxy" format.
+func parseBoundingBox(value string) (types.BoundingBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return types.BoundingBox{}, errors.New("bounding box requires exactly four coordinates")
+	}
+
+	var coordinates [4]float64
+	for idx, part := range parts {
+		coordinate, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return types.BoundingBox{}, err
+		}
+		if math.IsNaN(coordinate) || math.IsInf(coordinate, 0) {
+			return types.BoundingBox{}, errors.New("bounding box coordinates need to be finite numbers")
+		}
+		coordinates[idx] = coordinate
+	}
+
+	bbox := types.BoundingBox{
+		MinX: coordinates[0],
+		MinY: coordinates[1],
+		MaxX: coordinates[2],
+		MaxY: coordinates[3],
+	}
+	if bbox.MinX > bbox.MaxX || bbox.MinY > bbox.MaxY {
+		return types.BoundingBox{}, errors.New("minimal coordinates of bounding box exceed maximal coordinates")
+	}
+
+	return bbox, nil
+}
+
+// filterBoundingBox reads the bounding box from the request and restricts the
+// query to the objects intersecting it. The coordinates are interpreted as
+// WGS 84 unless another coordinate reference system is set in "bbox-crs".
+// If the parameters are invalid, the matching error is emitted and false is
+// returned.
+func filterBoundingBox(c *gin.Context, query *types.ObjectQuery) bool {
+	var parameters boundingBoxFilter
+	if err := c.ShouldBindQuery(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return false
+	}
+
+	if parameters.BBox == "" {
+		if parameters.CRS != "" {
+			c.Abort()
+			apiErrors.ErrInvalidBoundingBox.Emit(c)
+			return false
+		}
+		return true
+	}
+
+	bbox, err := parseBoundingBox(parameters.BBox)
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidBoundingBox
+		res.Errors = []error{err}
+		res.Emit(c)
+		return false
+	}
+
+	bbox.CRS = 4326
+	if parameters.CRS != "" {
+		var ok bool
+		bbox.CRS, ok = resolveCRS(c, parameters.CRS)
+		if !ok {
+			return false
+		}
+	}
+
+	query.Intersects(bbox)
+	return true
+}
@@ -0,0 +1,379 @@
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// The modes supported by the bulk import of objects.
+const (
+	// bulkModeReplace replaces all objects of the layer by the features.
+	// Objects whose key is not used by a feature are deleted
+	bulkModeReplace = "replace"
+
+	// bulkModeUpsert updates the objects using the keys of the features and
+	// inserts the features whose key is not used yet
+	bulkModeUpsert = "upsert"
+
+	// bulkModeAppend inserts the features and rejects the features whose key
+	// is already used
+	bulkModeAppend = "append"
+)
+
+// bulkSummary is the summary returned after a bulk import of objects.
+type bulkSummary struct {
+	Inserted int64             `json:"inserted"`
+	Updated  int64             `json:"updated"`
+	Deleted  int64             `json:"deleted"`
+	Rejected []rejectedFeature `json:"rejected"`
+}
+
+// rejectedFeature describes a feature which has not been imported.
+type rejectedFeature struct {
+	// Index contains the position of the feature in the request body
+	Index int `db:"position" json:"index"`
+
+	// Key contains the key of the feature if it could be read
+	Key *string `db:"key" json:"key"`
+
+	// Reason describes why the feature has been rejected
+	Reason string `db:"reason" json:"reason"`
+}
+
+// featureReader reads the features of a request body one at a time.
+// next returns [io.EOF] after the last feature.
+type featureReader interface {
+	next() (json.RawMessage, error)
+}
+
+// featureCollectionReader reads the features of a GeoJSON FeatureCollection
+// without decoding the whole collection at once.
+type featureCollectionReader struct {
+	decoder *json.Decoder
+	inArray bool
+}
+
+// expectDelimiter reads the next token and checks that it is the delimiter.
+func (r *featureCollectionReader) expectDelimiter(delimiter json.Delim) error {
+	token, err := r.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delimiter {
+		return fmt.Errorf("expected '%s' but found '%v'", delimiter, token)
+	}
+	return nil
+}
+
+// seekFeatures skips the members of the collection until the features are
+// reached.
+func (r *featureCollectionReader) seekFeatures() error {
+	if err := r.expectDelimiter('{'); err != nil {
+		return err
+	}
+
+	for r.decoder.More() {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		if token == "features" {
+			return r.expectDelimiter('[')
+		}
+
+		var skipped json.RawMessage
+		if err := r.decoder.Decode(&skipped); err != nil {
+			return err
+		}
+	}
+	return errors.New("the feature collection does not contain any features")
+}
+
+func (r *featureCollectionReader) next() (json.RawMessage, error) {
+	if !r.inArray {
+		if err := r.seekFeatures(); err != nil {
+			return nil, err
+		}
+		r.inArray = true
+	}
+
+	if !r.decoder.More() {
+		return nil, io.EOF
+	}
+
+	var feature json.RawMessage
+	err := r.decoder.Decode(&feature)
+	return feature, err
+}
+
+// featureSequenceReader reads the features of a GeoJSON text sequence or of
+// newline-delimited GeoJSON.
+type featureSequenceReader struct {
+	reader *bufio.Reader
+}
+
+func (r *featureSequenceReader) next() (json.RawMessage, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || len(line) == 0) {
+			return nil, err
+		}
+
+		// the records of a GeoJSON text sequence start with a record
+		// separator
+		line = bytes.TrimSpace(bytes.TrimLeft(line, "\x1e"))
+		if len(line) > 0 {
+			return line, nil
+		}
+	}
+}
+
+// newFeatureReader returns the reader matching the content type of the
+// request body.
+func newFeatureReader(c *gin.Context) featureReader {
+	switch c.ContentType() {
+	case mimeGeoJSONSeq, mimeNDJSON:
+		return &featureSequenceReader{reader: bufio.NewReader(c.Request.Body)}
+	default:
+		return &featureCollectionReader{decoder: json.NewDecoder(c.Request.Body)}
+	}
+}
+
+// bulkRow converts the feature into a row of the staging table. Features
+// which cannot be imported are stored with the reason of their rejection, so
+// their keys are still known when replacing the objects of the layer.
+func bulkRow(position int, raw json.RawMessage, seenKeys map[string]bool) []interface{} {
+	var feature featureInput
+	var key *string
+	reject := func(reason string) []interface{} {
+		return []interface{}{position, nil, key, "", nil, reason}
+	}
+
+	if err := json.Unmarshal(raw, &feature); err != nil {
+		return reject(err.Error())
+	}
+
+	if value, err := feature.key(""); err == nil && value != "" {
+		key = &value
+	}
+
+	if feature.Type != "Feature" {
+		return reject("the type of the feature needs to be 'Feature'")
+	}
+
+	if key == nil {
+		return reject("the feature needs to contain a non-empty string as key")
+	}
+
+	if seenKeys[*key] {
+		return reject("the key is used by a previous feature")
+	}
+	seenKeys[*key] = true
+
+	name, _, err := feature.name()
+	if err != nil {
+		return reject(err.Error())
+	}
+
+	if err := feature.decodeGeometry(); err != nil {
+		return reject(err.Error())
+	}
+	if feature.geometry == nil {
+		return reject("geometry must not be null")
+	}
+
+	return []interface{}{position, feature.geometry, key, name, feature.additionalProperties(), nil}
+}
+
+// execLayerQuery executes the named query formatted with the table of the
+// layer and returns the number of affected rows.
+func execLayerQuery(c *gin.Context, tx pgx.Tx, layer types.Layer, queryName string, args ...interface{}) (int64, error) {
+	query, err := db.Queries.Raw(queryName)
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(c, fmt.Sprintf(query, layer.TableName), args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// applyBulkObjects writes the features copied into the staging table to the
+// layer using the mode.
+func applyBulkObjects(c *gin.Context, tx pgx.Tx, layer types.Layer, mode string, crs int) (bulkSummary, error) {
+	var summary bulkSummary
+	var err error
+
+	query, err := db.Queries.Raw("reject-invalid-bulk-geometries")
+	if err != nil {
+		return bulkSummary{}, err
+	}
+	if _, err = tx.Exec(c, query); err != nil {
+		return bulkSummary{}, err
+	}
+
+	switch mode {
+	case bulkModeAppend:
+		_, err = execLayerQuery(c, tx, layer, "reject-existing-bulk-keys")
+	case bulkModeReplace:
+		summary.Deleted, err = execLayerQuery(c, tx, layer, "delete-missing-bulk-objects")
+	}
+	if err != nil {
+		return bulkSummary{}, err
+	}
+
+	if mode != bulkModeAppend {
+		summary.Updated, err = execLayerQuery(c, tx, layer, "update-bulk-objects", crs, layer.SRID())
+		if err != nil {
+			return bulkSummary{}, err
+		}
+	}
+
+	summary.Inserted, err = execLayerQuery(c, tx, layer, "insert-bulk-objects", crs, layer.SRID())
+	if err != nil {
+		return bulkSummary{}, err
+	}
+
+	query, err = db.Queries.Raw("get-rejected-bulk-objects")
+	if err != nil {
+		return bulkSummary{}, err
+	}
+
+	err = pgxscan.Select(c, tx, &summary.Rejected, query)
+	if err != nil {
+		return bulkSummary{}, err
+	}
+	if summary.Rejected == nil {
+		summary.Rejected = []rejectedFeature{}
+	}
+	return summary, nil
+}
+
+// BulkObjects imports the features of a GeoJSON FeatureCollection or a
+// GeoJSON text sequence into the layer. The features are streamed into a
+// staging table and written to the layer in a single transaction using the
+// mode set in the "mode" parameter. Features which cannot be imported are
+// rejected without aborting the import.
+func BulkObjects(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	var parameters struct {
+		Mode string `binding:"omitempty,oneof=replace upsert append" form:"mode"`
+	}
+	if err := c.ShouldBindQuery(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+	if parameters.Mode == "" {
+		parameters.Mode = bulkModeUpsert
+	}
+
+	crs, ok := inputCRS(c)
+	if !ok {
+		return
+	}
+
+	stagingQuery, err := db.Queries.Raw("create-bulk-staging-table")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	tx, err := db.Pool.Begin(c)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+	defer func() {
+		_ = tx.Rollback(c)
+	}()
+
+	if _, err = tx.Exec(c, stagingQuery); err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	// errors of the reader are kept separately as they are caused by a
+	// malformed request body instead of a failing database
+	var readErr error
+	reader := newFeatureReader(c)
+	seenKeys := make(map[string]bool)
+	position := 0
+	_, err = tx.CopyFrom(c, pgx.Identifier{"bulk_objects"},
+		[]string{"position", "geometry", "key", "name", "additional_properties", "reason"},
+		pgx.CopyFromFunc(func() ([]interface{}, error) {
+			raw, err := reader.next()
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			if err != nil {
+				readErr = err
+				return nil, err
+			}
+
+			row := bulkRow(position, raw, seenKeys)
+			position++
+			return row, nil
+		}),
+	)
+	if readErr != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidFeature
+		res.Errors = []error{fmt.Errorf("unable to read feature %d: %w", position, readErr)}
+		res.Emit(c)
+		return
+	}
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	// the table is locked after the features have been streamed into the
+	// staging table to prevent single objects from being written while the
+	// keys of the features are compared to the layer
+	if _, err = execLayerQuery(c, tx, layer, "lock-layer-table"); err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	summary, err := applyBulkObjects(c, tx, layer, parameters.Mode, crs)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	if err = tx.Commit(c); err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	forgetSchema(layer)
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,160 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"microservice/internal/config"
+	"microservice/middlewares"
+	"microservice/routes"
+)
+
+func Test_BulkObjects(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/-/bulk", middlewares.ResolveLayer, routes.BulkObjects)
+	router.DELETE("/content/:layerID/:key", middlewares.ResolveLayer, routes.DeleteObject)
+
+	key := fmt.Sprintf("test_object_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/content/e517edaa-8d7b-4f10-9cfc-56a7c56109f0/"+key, nil)
+		router.ServeHTTP(w, req)
+	})
+
+	body := fmt.Sprintf(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [8.21, 53.14]}, "properties": {"key": "%[1]s", "name": "Test Object"}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [8.21, 53.14]}, "properties": {"key": "%[1]s"}},
+		{"type": "Feature", "geometry": null, "properties": {}}
+	]}`, key)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/content/e517edaa-8d7b-4f10-9cfc-56a7c56109f0/-/bulk?mode=append", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/geo+json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	var summary struct {
+		Inserted int               `json:"inserted"`
+		Rejected []json.RawMessage `json:"rejected"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &summary)
+	assert.Equal(t, 1, summary.Inserted)
+	assert.Len(t, summary.Rejected, 2)
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_BulkObjects_Sequence(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/-/bulk", middlewares.ResolveLayer, routes.BulkObjects)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/content/e517edaa-8d7b-4f10-9cfc-56a7c56109f0/-/bulk?mode=append", strings.NewReader("\x1e{\"type\": \"Feature\", \"geometry\": null, \"properties\": {}}\n"))
+	req.Header.Set("Content-Type", "application/geo+json-seq")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_BulkObjects_InvalidMode(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/-/bulk", middlewares.ResolveLayer, routes.BulkObjects)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/content/e517edaa-8d7b-4f10-9cfc-56a7c56109f0/-/bulk?mode=merge", strings.NewReader(`{"type": "FeatureCollection", "features": []}`))
+	req.Header.Set("Content-Type", "application/geo+json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_BulkObjects_InvalidBody(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/-/bulk", middlewares.ResolveLayer, routes.BulkObjects)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/content/e517edaa-8d7b-4f10-9cfc-56a7c56109f0/-/bulk", strings.NewReader(`{"type": "FeatureCollection"}`))
+	req.Header.Set("Content-Type", "application/geo+json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// layerKeyPattern restricts the layer keys to valid and unquoted PostgreSQL
+// identifiers as the key is used as table name for the layer.
+var layerKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// The PostgreSQL error codes indicating that the layer key has been used by
+// another layer or table.
+const (
+	pgUniqueViolation = "23505"
+	pgDuplicateTable  = "42P07"
+)
+
+// isUniqueViolation checks if the error has been caused by a value violating
+// a unique constraint, e.g. an object key used by another object.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// layerDefinition contains the information required to create a new layer
+// and its backing table.
+type layerDefinition struct {
+	Name        string  `binding:"required" form:"name"        json:"name"`
+	Description *string `form:"description" json:"description"`
+	Key         string  `binding:"required" form:"key"         json:"key"`
+	CRS         int     `binding:"required" form:"crs"         json:"crs"`
+	Attribution *string `form:"attribution" json:"attribution"`
+	Private     bool    `form:"private"     json:"private"`
+	ChildLayer  *string `form:"childLayer"  json:"childLayer"`
+
+	SearchProperties []string `form:"searchProperties" json:"searchProperties"`
+
+	// childLayerID contains the id of the child layer which is resolved
+	// during the validation
+	childLayerID pgtype.UUID
+}
+
+// validate checks that the key of the layer is usable as table name and is
+// not used by another layer or table and that the coordinate reference system
+// is known to the database. The child layer is resolved if it is set.
+// If the definition is invalid, the matching error is emitted and false is
+// returned.
+func (d *layerDefinition) validate(c *gin.Context) bool {
+	if !layerKeyPattern.MatchString(d.Key) {
+		c.Abort()
+		apiErrors.ErrInvalidLayerKey.Emit(c)
+		return false
+	}
+
+	query, err := db.Queries.Raw("layer-table-exists")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	var tableExists bool
+	err = db.Pool.QueryRow(c, query, d.Key).Scan(&tableExists)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	if tableExists {
+		c.Abort()
+		apiErrors.ErrLayerKeyExists.Emit(c)
+		return false
+	}
+
+	knownCRS, err := crsExists(c, d.CRS)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	if !knownCRS {
+		c.Abort()
+		apiErrors.ErrUnknownCRS.Emit(c)
+		return false
+	}
+
+	if d.ChildLayer != nil {
+		childLayer, err := lookupLayer(c, *d.ChildLayer)
+		if err != nil {
+			c.Abort()
+			if pgxscan.NotFound(err) {
+				apiErrors.ErrUnknownLayer.Emit(c)
+				return false
+			}
+			_ = c.Error(err)
+			return false
+		}
+		d.childLayerID = childLayer.ID
+	}
+
+	return true
+}
+
+// create inserts the layer definition into the layers table and creates the
+// table containing the layer's objects using the supplied transaction.
+func (d layerDefinition) create(ctx context.Context, tx pgx.Tx) (types.Layer, error) {
+	query, err := db.Queries.Raw("crate-layer-definition")
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	var layer types.Layer
+	err = pgxscan.Get(ctx, tx, &layer, query, d.Name, d.Description, d.Key, d.CRS, d.Attribution, d.Private, d.childLayerID, d.SearchProperties)
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	query, err = db.Queries.Raw("create-layer-table")
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(query, layer.TableName))
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	query, err = db.Queries.Raw("update-geometry-srid")
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	_, err = tx.Exec(ctx, query, layer.TableName, d.CRS)
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	query, err = db.Queries.Raw("create-layer-spatial-index")
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(query, layer.TableName))
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	query, err = db.Queries.Raw("create-layer-search-indexes")
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(query, layer.TableName))
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	query, err = db.Queries.Raw("create-layer-property-index")
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	for _, property := range d.SearchProperties {
+		_, err = tx.Exec(ctx, fmt.Sprintf(query, layer.TableName, searchPropertyExpression(property)))
+		if err != nil {
+			return types.Layer{}, err
+		}
+	}
+
+	return layer, nil
+}
+
+func CreateLayer(c *gin.Context) {
+	var parameters layerDefinition
+	if err := c.ShouldBind(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrMissingParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	if !parameters.validate(c) {
+		return
+	}
+
+	tx, err := db.Pool.Begin(c)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+	defer func() {
+		_ = tx.Rollback(c)
+	}()
+
+	layer, err := parameters.create(c, tx)
+	if err != nil {
+		c.Abort()
+		// another request may have claimed the key since the validation
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == pgUniqueViolation || pgErr.Code == pgDuplicateTable) {
+			apiErrors.ErrLayerKeyExists.Emit(c)
+			return
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	if err = tx.Commit(c); err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, layer)
+}
@@ -0,0 +1,171 @@
+package routes_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"microservice/internal/config"
+	"microservice/internal/db"
+	"microservice/routes"
+)
+
+func Test_CreateLayer(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/", routes.CreateLayer)
+
+	layerKey := fmt.Sprintf("test_layer_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = db.Pool.Exec(context.Background(), fmt.Sprintf(`DROP TABLE IF EXISTS geodata.%s`, layerKey))
+		_, _ = db.Pool.Exec(context.Background(), `DELETE FROM geodata.layers WHERE "table" = $1`, layerKey)
+	})
+
+	body := fmt.Sprintf(`{"name": "Test Layer", "key": "%s", "crs": 25832}`, layerKey)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+
+	// creating the layer a second time needs to be rejected
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors = v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_CreateLayer_InvalidKey(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/", routes.CreateLayer)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"name": "Test Layer", "key": "Invalid Key; DROP TABLE geodata.layers", "crs": 4326}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_CreateLayer_UnknownCRS(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/", routes.CreateLayer)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"name": "Test Layer", "key": "test_unknown_crs", "crs": 999999}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_CreateLayer_MissingParameters(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/", routes.CreateLayer)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"name": "Test Layer"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// crs84 is the identifier of the WGS 84 coordinate reference system with
+// longitude/latitude axis order used by GeoJSON and OGC API Features.
+const crs84 = "http://www.opengis.net/def/crs/OGC/1.3/CRS84"
+
+// advertisedCRS contains the EPSG codes of the coordinate reference systems
+// advertised for every layer in addition to WGS 84 and the layer's own
+// coordinate reference system. Any other coordinate reference system known
+// to the database may be requested as well.
+var advertisedCRS = []int{3857, 4258, 25832, 25833}
+
+// epsgReferencePattern matches the supported references to an EPSG code:
+// the plain code, "EPSG:<code>", the OGC URI and the OGC URN.
+var epsgReferencePattern = regexp.MustCompile(`(?i)^(?:epsg:|https?://www\.opengis\.net/def/crs/epsg/0/|urn:ogc:def:crs:epsg:[0-9.]*:)?(\d+)$`)
+
+// parseCRS extracts the EPSG code from a reference to a coordinate reference
+// system. The OGC CRS84 reference is mapped to EPSG:4326 as PostGIS always
+// uses the longitude/latitude axis order.
+func parseCRS(reference string) (int, bool) {
+	reference = strings.TrimSpace(reference)
+	if strings.EqualFold(reference, crs84) || strings.EqualFold(reference, "CRS84") {
+		return 4326, true
+	}
+
+	match := epsgReferencePattern.FindStringSubmatch(reference)
+	if match == nil {
+		return 0, false
+	}
+
+	epsgCode, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return epsgCode, true
+}
+
+// crsExists checks if the coordinate reference system is known to the
+// database.
+func crsExists(ctx context.Context, epsgCode int) (bool, error) {
+	query, err := db.Queries.Raw("crs-exists")
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = db.Pool.QueryRow(ctx, query, epsgCode).Scan(&exists)
+	return exists, err
+}
+
+// resolveCRS parses the reference to a coordinate reference system and
+// checks that it is known to the database.
+// If the reference is invalid or unknown, the matching error is emitted and
+// false is returned.
+func resolveCRS(c *gin.Context, reference string) (int, bool) {
+	epsgCode, ok := parseCRS(reference)
+	if !ok {
+		c.Abort()
+		res := apiErrors.ErrUnknownCRS
+		res.Errors = []error{fmt.Errorf("unable to parse coordinate reference system '%s'", reference)}
+		res.Emit(c)
+		return 0, false
+	}
+
+	exists, err := crsExists(c, epsgCode)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return 0, false
+	}
+
+	if !exists {
+		c.Abort()
+		res := apiErrors.ErrUnknownCRS
+		res.Errors = []error{fmt.Errorf("EPSG:%d is not known to the database", epsgCode)}
+		res.Emit(c)
+		return 0, false
+	}
+
+	return epsgCode, true
+}
+
+// supportedCRS returns the OGC URIs of the coordinate reference systems
+// advertised for the layer. WGS 84 is listed first as it is the default
+// output, followed by the layer's own coordinate reference system.
+func supportedCRS(layer types.Layer) []string {
+	crsList := []string{crs84}
+	if layer.SRID() != 4326 {
+		crsList = append(crsList, crsURI(layer.SRID()))
+	}
+	for _, epsgCode := range advertisedCRS {
+		if epsgCode != layer.SRID() {
+			crsList = append(crsList, crsURI(epsgCode))
+		}
+	}
+	return crsList
+}
+
+// requestedCRS resolves the coordinate reference system requested for the
+// output and announces it in the Content-Crs header. WGS 84 is used if no
+// coordinate reference system has been requested.
+// If the coordinate reference system is unknown, the matching error is
+// emitted and false is returned.
+func requestedCRS(c *gin.Context, reference string) (int, bool) {
+	epsgCode := 4326
+	if reference != "" {
+		var ok bool
+		epsgCode, ok = resolveCRS(c, reference)
+		if !ok {
+			return 0, false
+		}
+	}
+
+	c.Header("Content-Crs", "<"+crsURI(epsgCode)+">")
+	return epsgCode, true
+}
+
+// outputCRS resolves the coordinate reference system requested in the "crs"
+// parameter, sets it on the query and announces it in the Content-Crs header.
+// If the coordinate reference system is unknown, the matching error is
+// emitted and false is returned.
+func outputCRS(c *gin.Context, query *types.ObjectQuery, reference string) bool {
+	epsgCode, ok := requestedCRS(c, reference)
+	if !ok {
+		return false
+	}
+	query.CRS = epsgCode
+	return true
+}
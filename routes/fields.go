@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestedFields returns the properties listed in the comma-separated
+// "fields" parameter. If the parameter is not set, nil is returned to select
+// all properties.
+func requestedFields(c *gin.Context) []string {
+	rawFields, isSet := c.GetQuery("fields")
+	if !isSet {
+		return nil
+	}
+
+	fields := []string{}
+	for _, field := range strings.Split(rawFields, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,66 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"microservice/internal/cql2"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// The encodings of CQL2 filters supported in the "filter-lang" parameter.
+const (
+	filterLanguageText = "cql2-text"
+	filterLanguageJSON = "cql2-json"
+)
+
+// attributeFilter contains the query parameters filtering the objects by
+// their attributes.
+type attributeFilter struct {
+	Filter   string `form:"filter"`
+	Language string `binding:"omitempty,oneof=cql2-text cql2-json" form:"filter-lang"`
+}
+
+// filterAttributes reads the CQL2 filter from the request and restricts the
+// query to the objects matching it.
+// If the filter is invalid, the matching error is emitted and false is
+// returned.
+func filterAttributes(c *gin.Context, query *types.ObjectQuery) bool {
+	var parameters attributeFilter
+	if err := c.ShouldBindQuery(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return false
+	}
+
+	if parameters.Filter == "" {
+		return true
+	}
+
+	var expression cql2.Expression
+	var err error
+	switch parameters.Language {
+	case filterLanguageJSON:
+		expression, err = cql2.ParseJSON([]byte(parameters.Filter))
+	default:
+		expression, err = cql2.ParseText(parameters.Filter)
+	}
+
+	var condition string
+	if err == nil {
+		condition, err = expression.SQL(query.Arg)
+	}
+
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidFilter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return false
+	}
+
+	query.Where(condition)
+	return true
+}
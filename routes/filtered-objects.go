@@ -1,162 +1,117 @@
 package routes
 
 import (
-	"fmt"
+	"errors"
 	"strings"
 
-	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 
-	"microservice/internal/db"
 	apiErrors "microservice/internal/errors"
 	"microservice/types"
 )
 
 func FilteredLayerContents(c *gin.Context) {
 	var parameters struct {
-		Relation   string   `binding:"required" form:"relation"    json:"relation"`
-		Keys       []string `binding:"required" form:"key"         json:"key"`
-		OtherLayer string   `binding:"required" form:"other_layer" json:"other_layer"`
+		relationParameters
+		Keys       []string `form:"key"         json:"key"`
+		OtherLayer string   `form:"other_layer" json:"other_layer"`
+		Predicates []string `form:"predicate"   json:"predicate"`
+		Match      string   `binding:"omitempty,oneof=any all" form:"match" json:"match"`
 	}
 
 	if err := c.ShouldBind(&parameters); err != nil {
 		c.Abort()
-		res := apiErrors.ErrMissingParameter
+		res := apiErrors.ErrInvalidParameter
 		res.Errors = []error{err}
 		res.Emit(c)
 		return
 	}
 
-	query, err := db.Queries.Raw("get-layer")
-	if err != nil {
+	// the key and other layer are only optional if the predicates are used
+	// to describe the filter
+	useKeys := len(parameters.Predicates) == 0 || len(parameters.Keys) > 0 || parameters.OtherLayer != ""
+	if useKeys && (len(parameters.Keys) == 0 || parameters.OtherLayer == "") {
 		c.Abort()
-		_ = c.Error(err)
+		res := apiErrors.ErrMissingParameter
+		res.Errors = []error{errors.New("key and other_layer are required unless predicates are set")}
+		res.Emit(c)
+		return
+	}
+
+	format, ok := outputFormat(c)
+	if !ok {
 		return
 	}
 
-	if err = uuid.Validate(parameters.OtherLayer); err != nil {
-		query, err = db.Queries.Raw("get-layer-by-url-key")
-		if err != nil {
-			c.Abort()
-			_ = c.Error(err)
+	layers := make(map[string]types.Layer)
+	var predicates []spatialPredicate
+	if useKeys {
+		relation, ok := parameters.spatialRelation(c)
+		if !ok {
 			return
 		}
-	}
 
-	var topLayer types.Layer
-	err = pgxscan.Get(c, db.Pool, &topLayer, query, parameters.OtherLayer)
-	if err != nil {
-		c.Abort()
-		if pgxscan.NotFound(err) {
-			apiErrors.ErrUnknownTopLayer.Emit(c)
+		topLayer, ok := predicateLayer(c, parameters.OtherLayer, layers)
+		if !ok {
 			return
 		}
-		_ = c.Error(err)
-		return
-	}
 
-	var objects []types.Object
-	switch parameters.Relation {
-	case "within":
-		objects = filteredLayerContents_Within(c, topLayer, parameters.Keys)
-	case "overlaps":
-		objects = filteredLayerContents_Overlaps(c, topLayer, parameters.Keys)
-	case "contains":
-		objects = filteredLayerContents_Contains(c, topLayer, parameters.Keys)
-	default:
-		c.Abort()
-		apiErrors.ErrUnsupportedSpatialRelation.Emit(c)
-		return
+		for _, key := range parameters.Keys {
+			predicates = append(predicates, spatialPredicate{relation: relation, layer: topLayer, key: key})
+		}
 	}
 
-	if len(objects) == 0 {
-		c.Status(204)
-		return
+	for _, rawPredicate := range parameters.Predicates {
+		predicate, ok := parsePredicate(c, rawPredicate, layers)
+		if !ok {
+			return
+		}
+		predicates = append(predicates, predicate)
 	}
 
-	c.JSON(200, objects)
-}
-
-func filteredLayerContents_Within(c *gin.Context, topLayer types.Layer, keys []string) []types.Object {
 	layerInterface, _ := c.Get("layer")
 	baseLayer, _ := layerInterface.(types.Layer)
 
-	var queryParts []string
-	var queryParams []interface{}
-	for idx, key := range keys {
-		queryParts = append(queryParts,
-			fmt.Sprintf(`ST_WITHIN(st_transform(geometry, 4326), (SELECT st_transform(geometry, 4326) FROM geodata.%s WHERE key = $%d))`,
-				topLayer.TableName, idx+1))
-		queryParams = append(queryParams, key)
+	contentQuery := baseLayer.ContentQuery()
+	contentQuery.Fields = requestedFields(c)
+	if !filterAttributes(c, contentQuery) || !geometryOutput(c, contentQuery) || !outputCRS(c, contentQuery, c.Query("crs")) {
+		return
 	}
 
-	queryCondition := strings.Join(queryParts, " OR ")
-
-	baseQuery, _ := baseLayer.ContentQuery()
-	query := fmt.Sprintf("%s WHERE %s;", strings.TrimSuffix(baseQuery, ";"), queryCondition)
-	var layerContents []types.Object
-	err := pgxscan.Select(c, db.Pool, &layerContents, query, queryParams...)
-	if err != nil {
-		c.Abort()
-		_ = c.Error(err)
-		return nil
+	page, ok := paginate(c, contentQuery, isStreamed(format))
+	if !ok {
+		return
 	}
 
-	return layerContents
-}
-
-func filteredLayerContents_Overlaps(c *gin.Context, topLayer types.Layer, keys []string) []types.Object {
-	layerInterface, _ := c.Get("layer")
-	baseLayer, _ := layerInterface.(types.Layer)
-
-	var queryParts []string
-	var queryParams []interface{}
-	for idx, key := range keys {
-		queryParts = append(queryParts,
-			fmt.Sprintf(`ST_OVERLAPS(st_transform(geometry, 4326), (SELECT st_transform(geometry, 4326) FROM geodata.%s WHERE key = $%d))`,
-				topLayer.TableName, idx+1))
-		queryParams = append(queryParams, key)
+	conditions := make([]string, len(predicates))
+	for idx, predicate := range predicates {
+		conditions[idx] = predicate.condition(contentQuery, baseLayer)
 	}
 
-	queryCondition := strings.Join(queryParts, " OR ")
-
-	baseQuery, _ := baseLayer.ContentQuery()
-	query := fmt.Sprintf("%s WHERE %s;", strings.TrimSuffix(baseQuery, ";"), queryCondition)
-	var layerContents []types.Object
-	err := pgxscan.Select(c, db.Pool, &layerContents, query, queryParams...)
-	if err != nil {
-		c.Abort()
-		_ = c.Error(err)
-		return nil
+	operator := " OR "
+	if parameters.Match == "all" {
+		operator = " AND "
 	}
+	contentQuery.Where(strings.Join(conditions, operator))
 
-	return layerContents
-}
-
-func filteredLayerContents_Contains(c *gin.Context, topLayer types.Layer, keys []string) []types.Object {
-	layerInterface, _ := c.Get("layer")
-	baseLayer, _ := layerInterface.(types.Layer)
+	if isStreamed(format) {
+		streamObjects(c, format, contentQuery)
+		return
+	}
 
-	var queryParts []string
-	var queryParams []interface{}
-	for idx, key := range keys {
-		queryParts = append(queryParts,
-			fmt.Sprintf(`ST_CONTAINS(st_transform(geometry, 4326), (SELECT st_transform(geometry, 4326) FROM geodata.%s WHERE key = $%d))`,
-				topLayer.TableName, idx+1))
-		queryParams = append(queryParams, key)
+	objects, ok := selectObjects(c, contentQuery)
+	if !ok {
+		return
 	}
 
-	queryCondition := strings.Join(queryParts, " OR ")
+	if !setPaginationHeaders(c, page, contentQuery, objects) {
+		return
+	}
 
-	baseQuery, _ := baseLayer.ContentQuery()
-	query := fmt.Sprintf("%s WHERE %s;", strings.TrimSuffix(baseQuery, ";"), queryCondition)
-	var layerContents []types.Object
-	err := pgxscan.Select(c, db.Pool, &layerContents, query, queryParams...)
-	if err != nil {
-		c.Abort()
-		_ = c.Error(err)
-		return nil
+	if len(objects) == 0 && format != formatGeoJSON {
+		c.Status(204)
+		return
 	}
-	return layerContents
+
+	writeObjects(c, format, objects)
 }
@@ -216,3 +216,242 @@ func Test_FilteredObjects_MissingKeys(t *testing.T) {
 		}
 	}
 }
+
+func Test_FilteredObjects_GeoJSON(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.GET("/content/:layerID/filtered", middlewares.ResolveLayer, routes.FilteredLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/content/1e694f36-cf68-426a-b6a3-7660163b03e6/filtered?relation=contains&other_layer=e517edaa-8d7b-4f10-9cfc-56a7c56109f0&key=03101&f=geojson", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/geo+json", w.Header().Get("Content-Type"))
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpRequestResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_FilteredObjects_DWithin(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+
+	router.GET("/content/:layerID/filtered", middlewares.ResolveLayer, routes.FilteredLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/content/1e694f36-cf68-426a-b6a3-7660163b03e6/filtered?relation=dwithin&distance=1000&other_layer=e517edaa-8d7b-4f10-9cfc-56a7c56109f0&key=03101", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpRequestResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_FilteredObjects_DWithinMissingDistance(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+
+	router.GET("/content/:layerID/filtered", middlewares.ResolveLayer, routes.FilteredLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/content/1e694f36-cf68-426a-b6a3-7660163b03e6/filtered?relation=dwithin&other_layer=e517edaa-8d7b-4f10-9cfc-56a7c56109f0&key=03101", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_FilteredObjects_RelatePattern(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+
+	router.GET("/content/:layerID/filtered", middlewares.ResolveLayer, routes.FilteredLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/content/1e694f36-cf68-426a-b6a3-7660163b03e6/filtered?relate=T*F**F***&other_layer=e517edaa-8d7b-4f10-9cfc-56a7c56109f0&key=03101", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpRequestResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_FilteredObjects_InvalidRelatePattern(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+
+	router.GET("/content/:layerID/filtered", middlewares.ResolveLayer, routes.FilteredLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/content/1e694f36-cf68-426a-b6a3-7660163b03e6/filtered?relate=T*F**F&other_layer=e517edaa-8d7b-4f10-9cfc-56a7c56109f0&key=03101", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_FilteredObjects_MatchAllPredicates(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+
+	router.GET("/content/:layerID/filtered", middlewares.ResolveLayer, routes.FilteredLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/content/e1fde37d-69aa-43fc-8338-588dc09f7ff2/filtered?match=all&relation=within&other_layer=1e694f36-cf68-426a-b6a3-7660163b03e6&key=02102&predicate=intersects:1e694f36-cf68-426a-b6a3-7660163b03e6:02102", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpRequestResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_FilteredObjects_InvalidPredicate(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+
+	router.GET("/content/:layerID/filtered", middlewares.ResolveLayer, routes.FilteredLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/content/e1fde37d-69aa-43fc-8338-588dc09f7ff2/filtered?predicate=within:02102", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_FilteredLayerContents_InvalidMatch(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.GET("/content/:layerID/filtered", middlewares.ResolveLayer, routes.FilteredLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/content/1e694f36-cf68-426a-b6a3-7660163b03e6/filtered?relation=contains&other_layer=e517edaa-8d7b-4f10-9cfc-56a7c56109f0&key=03101&match=foo", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
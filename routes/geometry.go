@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// geometryParameters contains the query parameters controlling which
+// representation of the geometries is returned and how they are simplified
+// and encoded.
+type geometryParameters struct {
+	Geometry  string  `binding:"omitempty,oneof=none centroid bbox full" form:"geometry"`
+	Simplify  float64 `binding:"omitempty,gt=0"                          form:"simplify"`
+	Zoom      *int    `binding:"omitempty,min=0,max=24"                  form:"zoom"`
+	Precision *int    `binding:"omitempty,min=0,max=15"                  form:"precision"`
+}
+
+// bindGeometryParameters reads the representation, simplification and
+// precision of the geometries from the request.
+// If the parameters are invalid, the matching error is emitted and false is
+// returned.
+func bindGeometryParameters(c *gin.Context) (geometryParameters, bool) {
+	var parameters geometryParameters
+	err := c.ShouldBindQuery(&parameters)
+	if err == nil && parameters.Simplify > 0 && parameters.Zoom != nil {
+		err = errors.New("simplify and zoom are mutually exclusive")
+	}
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return geometryParameters{}, false
+	}
+	return parameters, true
+}
+
+// apply sets the representation, simplification and precision on the query.
+func (parameters geometryParameters) apply(query *types.ObjectQuery) {
+	query.Geometry = types.GeometryMode(parameters.Geometry)
+	switch {
+	case parameters.Simplify > 0:
+		query.Simplify(parameters.Simplify)
+	case parameters.Zoom != nil:
+		query.SimplifyForZoom(*parameters.Zoom)
+	}
+	query.Precision = parameters.Precision
+}
+
+// geometryOutput reads the representation, simplification and precision of
+// the geometries from the request and sets them on the query.
+// If the parameters are invalid, the matching error is emitted and false is
+// returned.
+func geometryOutput(c *gin.Context, query *types.ObjectQuery) bool {
+	parameters, ok := bindGeometryParameters(c)
+	if !ok {
+		return false
+	}
+	parameters.apply(query)
+	return true
+}
@@ -1,33 +1,117 @@
 package routes
 
 import (
+	"fmt"
 	"net/http"
-	"sync"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/gin-gonic/gin"
 
+	"microservice/internal/config"
 	"microservice/internal/db"
 	apiErrors "microservice/internal/errors"
 	"microservice/types"
 )
 
-func IdentifyObject(c *gin.Context) {
-	var parameters struct {
-		Keys []string `binding:"required" form:"key" json:"keys"`
+// identifiedObjects contains the objects found by an identify request grouped
+// by the key of their layer and their own key.
+type identifiedObjects map[string]map[string]types.Object
+
+// identifyOutput contains the output options shared by all queries executed
+// for an identify request.
+type identifyOutput struct {
+	format   string
+	geometry geometryParameters
+	crs      int
+	fields   []string
+}
+
+// bindIdentifyOutput reads the output options of an identify request.
+// If an option is invalid, the matching error is emitted and false is
+// returned.
+func bindIdentifyOutput(c *gin.Context) (identifyOutput, bool) {
+	var output identifyOutput
+	var ok bool
+
+	output.format, ok = outputFormat(c)
+	if !ok {
+		return identifyOutput{}, false
 	}
 
-	if err := c.ShouldBind(&parameters); err != nil {
+	// the identified objects are grouped by their layer and can therefore
+	// not be streamed
+	if isStreamed(output.format) {
 		c.Abort()
-		apiErrors.ErrMissingParameter.Emit(c)
+		apiErrors.ErrUnsupportedOutputFormat.Emit(c)
+		return identifyOutput{}, false
+	}
+
+	output.geometry, ok = bindGeometryParameters(c)
+	if !ok {
+		return identifyOutput{}, false
+	}
+
+	output.crs, ok = requestedCRS(c, c.Query("crs"))
+	if !ok {
+		return identifyOutput{}, false
+	}
+
+	output.fields = requestedFields(c)
+	return output, true
+}
+
+// apply sets the output options on the query.
+func (output identifyOutput) apply(query *types.ObjectQuery) {
+	query.CRS = output.crs
+	query.Fields = output.fields
+	output.geometry.apply(query)
+}
+
+// applyOutput sets the requested precision on the object and removes the
+// name from the output if it has not been selected.
+func (output identifyOutput) applyOutput(object types.Object) types.Object {
+	if output.fields != nil && !slices.Contains(output.fields, "name") {
+		object = object.WithoutName()
+	}
+	if output.geometry.Precision == nil {
+		return object
+	}
+	return object.WithPrecision(*output.geometry.Precision)
+}
+
+// write writes the identified objects in the requested format.
+func (output identifyOutput) write(c *gin.Context, objects identifiedObjects) {
+	if output.format == formatGeoJSON {
+		// GeoJSON does not allow grouping the features, therefore the layer
+		// is added to the properties of each feature
+		collection := types.NewFeatureCollection(nil)
+		for layer, layerObjects := range objects {
+			for _, object := range layerObjects {
+				feature := object.Feature()
+				feature.Properties["layer"] = layer
+				collection.Features = append(collection.Features, feature)
+			}
+		}
+		c.Header("Content-Type", mimeGeoJSON)
+		c.JSON(http.StatusOK, collection)
 		return
 	}
 
+	c.JSON(http.StatusOK, objects)
+}
+
+// accessibleLayers returns all layers which may be accessed by the client.
+// If the layers cannot be read, the error is set on the context and false is
+// returned.
+func accessibleLayers(c *gin.Context) ([]types.Layer, bool) {
 	query, err := db.Queries.Raw("get-layers")
 	if err != nil {
 		c.Abort()
 		_ = c.Error(err)
-		return
+		return nil, false
 	}
 
 	var layers []types.Layer
@@ -35,50 +119,168 @@ func IdentifyObject(c *gin.Context) {
 	if err != nil {
 		c.Abort()
 		_ = c.Error(err)
+		return nil, false
+	}
+
+	return layers, true
+}
+
+// identifiedObject is an object selected by the query combining the layers
+// of an identify request.
+type identifiedObject struct {
+	Layer string `db:"layer"`
+	types.Object
+}
+
+// checkLayerTables verifies that the tables of all layers exist as the
+// combined query of an identify request fails otherwise.
+// If a table is missing, the matching error is emitted and false is returned.
+func checkLayerTables(c *gin.Context, layers []types.Layer) bool {
+	query, err := db.Queries.Raw("get-missing-layer-tables")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	tables := make([]string, len(layers))
+	for idx, layer := range layers {
+		tables[idx] = layer.TableName
+	}
+
+	var missingTables []string
+	err = pgxscan.Select(c, db.Pool, &missingTables, query, tables)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	if len(missingTables) > 0 {
+		c.Abort()
+		res := apiErrors.ErrMissingLayerTable
+		for _, table := range missingTables {
+			res.Errors = append(res.Errors, fmt.Errorf("the table of layer '%s' does not exist", table))
+		}
+		res.Emit(c)
+		return false
+	}
+
+	return true
+}
+
+// identify selects the objects of all layers matching the conditions added by
+// restrict using a single query combining the queries of the layers.
+// restrict is required to register the same arguments in the same order for
+// every layer as the combined query shares the arguments of the queries.
+// If the objects cannot be selected, the matching error is emitted and false
+// is returned.
+func identify(c *gin.Context, layers []types.Layer, output identifyOutput, restrict func(*types.ObjectQuery, types.Layer)) (identifiedObjects, bool) {
+	objects := make(identifiedObjects)
+	if len(layers) == 0 {
+		return objects, true
+	}
+
+	if !checkLayerTables(c, layers) {
+		return nil, false
+	}
+
+	var args []interface{}
+	var sharedArgs int
+	layerQueries := make([]string, len(layers))
+	for idx, layer := range layers {
+		contentQuery := layer.ContentQuery()
+		restrict(contentQuery, layer)
+		output.apply(contentQuery)
+
+		query, err := contentQuery.SQL()
+		if err != nil {
+			c.Abort()
+			_ = c.Error(err)
+			return nil, false
+		}
+
+		if idx == 0 {
+			args = contentQuery.Args()
+			sharedArgs = len(args)
+		}
+
+		// the layer keys are passed as additional arguments after the
+		// shared arguments of the layer queries
+		layerArg := "$" + strconv.Itoa(sharedArgs+idx+1)
+		layerQueries[idx] = "SELECT " + layerArg + "::text AS layer, objects.* FROM (" + query + ") AS objects"
+	}
+	for _, layer := range layers {
+		args = append(args, layer.TableName)
+	}
+
+	var rows []identifiedObject
+	err := pgxscan.Select(c, db.Pool, &rows, strings.Join(layerQueries, " UNION ALL "), args...)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	for _, row := range rows {
+		if objects[row.Layer] == nil {
+			objects[row.Layer] = make(map[string]types.Object)
+		}
+		objects[row.Layer][row.Key] = output.applyOutput(row.Object)
+	}
+	return objects, true
+}
+
+func IdentifyObject(c *gin.Context) {
+	var parameters struct {
+		Keys      []string `form:"key"        json:"keys"`
+		KeyPrefix string   `form:"key_prefix" json:"keyPrefix"`
+	}
+
+	if err := c.ShouldBind(&parameters); err != nil || (len(parameters.Keys) == 0 && parameters.KeyPrefix == "") {
+		c.Abort()
+		apiErrors.ErrMissingParameter.Emit(c)
 		return
 	}
 
-	objects := make(map[string]map[string]types.Object)
-	var wg sync.WaitGroup
-	var mapLock sync.Mutex
-	for _, k := range parameters.Keys {
-		wg.Add(1)
-		go func(key string) {
-			defer wg.Done()
-			for _, l := range layers {
-				query, err = l.FilteredContentQuery()
-				if err != nil {
-					_ = c.Error(err)
-					continue
-				}
-				var object types.Object
-				err = pgxscan.Get(c, db.Pool, &object, query, key)
-				if err != nil {
-					if pgxscan.NotFound(err) {
-						continue
-					}
-					c.Abort()
-					_ = c.Error(err)
-					continue
-				}
-				mapLock.Lock()
-				if objects[l.TableName] == nil {
-					objects[l.TableName] = make(map[string]types.Object)
-				}
-				objects[l.TableName][key] = object
-				mapLock.Unlock()
-			}
-		}(k)
+	if len(parameters.Keys) > config.IdentifyMaxKeys {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{fmt.Errorf("at most %d keys may be identified at once", config.IdentifyMaxKeys)}
+		res.Emit(c)
+		return
+	}
+
+	output, ok := bindIdentifyOutput(c)
+	if !ok {
+		return
+	}
 
+	layers, ok := accessibleLayers(c)
+	if !ok {
+		return
 	}
-	wg.Wait()
-	if c.IsAborted() {
+
+	objects, ok := identify(c, layers, output, func(query *types.ObjectQuery, _ types.Layer) {
+		var conditions []string
+		if len(parameters.Keys) > 0 {
+			conditions = append(conditions, "key = ANY("+query.Arg(parameters.Keys)+"::text[])")
+		}
+		if parameters.KeyPrefix != "" {
+			conditions = append(conditions, "key LIKE "+query.Arg(types.PrefixPattern(parameters.KeyPrefix)))
+			// a short prefix may match most objects of a layer
+			query.Limit = config.IdentifyMaxKeys
+		}
+		query.Where(strings.Join(conditions, " OR "))
+	})
+	if !ok {
 		return
 	}
+
 	if len(objects) == 0 {
 		apiErrors.ErrUnknownObject.Emit(c)
 		return
 	}
 
-	c.JSON(http.StatusOK, objects)
+	output.write(c, objects)
 }
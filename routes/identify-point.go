@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// pointParameters contains the location identified by a point-based identify
+// request. The tolerance is expressed in meters.
+type pointParameters struct {
+	Longitude *float64 `binding:"required,min=-180,max=180" form:"lon"`
+	Latitude  *float64 `binding:"required,min=-90,max=90"   form:"lat"`
+	Tolerance float64  `binding:"min=0"                     form:"tolerance"`
+}
+
+// IdentifyPoint returns the objects of every accessible layer which are
+// located at a point. The layers may be restricted using the "layer"
+// parameter.
+func IdentifyPoint(c *gin.Context) {
+	var parameters pointParameters
+	if err := c.ShouldBindQuery(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	output, ok := bindIdentifyOutput(c)
+	if !ok {
+		return
+	}
+
+	layers, ok := requestedLayers(c)
+	if !ok {
+		return
+	}
+
+	objects, ok := identify(c, layers, output, func(query *types.ObjectQuery, layer types.Layer) {
+		point := fmt.Sprintf("ST_SetSRID(ST_MakePoint(%s, %s), 4326)",
+			query.Arg(*parameters.Longitude), query.Arg(*parameters.Latitude))
+		if parameters.Tolerance > 0 {
+			query.Related(types.SpatialRelation{Function: "ST_DWithin", Distance: parameters.Tolerance}, point)
+			return
+		}
+		query.Related(types.SpatialRelation{Function: "ST_Intersects"}, fmt.Sprintf("ST_Transform(%s, %d)", point, layer.SRID()))
+	})
+	if !ok {
+		return
+	}
+
+	output.write(c, objects)
+}
@@ -0,0 +1,129 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"microservice/internal/config"
+	"microservice/routes"
+)
+
+func Test_IdentifyPoint(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.GET("/identify/point", routes.IdentifyPoint)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/identify/point?lon=9.73&lat=52.37", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpRequestResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_IdentifyPoint_Tolerance(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.GET("/identify/point", routes.IdentifyPoint)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/identify/point?lon=9.73&lat=52.37&tolerance=500&layer=1e694f36-cf68-426a-b6a3-7660163b03e6&f=geojson", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpRequestResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_IdentifyPoint_MissingLocation(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.GET("/identify/point", routes.IdentifyPoint)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/identify/point?lon=9.73", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_IdentifyPoint_UnknownLayer(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.GET("/identify/point", routes.IdentifyPoint)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/identify/point?lon=9.73&lat=52.37&layer=00000000-0000-0000-0000-000000000000", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpRequestResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
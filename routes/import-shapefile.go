@@ -0,0 +1,186 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// importBatchSize limits the number of objects sent to the database in a
+// single batch while importing a shapefile.
+const importBatchSize = 1000
+
+// shapefileImport is the summary returned after importing a shapefile.
+type shapefileImport struct {
+	Layer    types.Layer `json:"layer"`
+	Imported int         `json:"imported"`
+	Skipped  int         `json:"skipped"`
+}
+
+func ImportShapefile(c *gin.Context) {
+	var parameters struct {
+		Layer     string `form:"layer"`
+		KeyField  string `binding:"required" form:"key_field"`
+		NameField string `binding:"required" form:"name_field"`
+	}
+
+	if err := c.ShouldBind(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrMissingParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	archive, ok := readShapefile(c)
+	if !ok {
+		return
+	}
+
+	if !slices.Contains(archive.Fields, parameters.KeyField) || !slices.Contains(archive.Fields, parameters.NameField) {
+		c.Abort()
+		apiErrors.ErrUnknownShapefileField.Emit(c)
+		return
+	}
+
+	sourceCRS, ok := shapefileEPSGCode(c, archive)
+	if !ok {
+		return
+	}
+
+	if sourceCRS == 0 {
+		c.Abort()
+		apiErrors.ErrUndetectableShapefileCRS.Emit(c)
+		return
+	}
+
+	// the objects are either imported into an existing layer or a new layer
+	// is created using the layer definition contained in the request
+	var layer types.Layer
+	var definition *layerDefinition
+	if parameters.Layer != "" {
+		var err error
+		layer, err = lookupLayer(c, parameters.Layer)
+		if err != nil {
+			c.Abort()
+			if pgxscan.NotFound(err) {
+				apiErrors.ErrUnknownLayer.Emit(c)
+				return
+			}
+			_ = c.Error(err)
+			return
+		}
+
+		if layer.Private && !c.GetBool("AccessPrivateLayers") {
+			c.Abort()
+			apiErrors.ErrLayerPrivate.Emit(c)
+			return
+		}
+	} else {
+		definition = &layerDefinition{CRS: sourceCRS}
+		if err := c.ShouldBind(definition); err != nil {
+			c.Abort()
+			res := apiErrors.ErrMissingParameter
+			res.Errors = []error{err}
+			res.Emit(c)
+			return
+		}
+
+		if !definition.validate(c) {
+			return
+		}
+	}
+
+	tx, err := db.Pool.Begin(c)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+	defer func() {
+		_ = tx.Rollback(c)
+	}()
+
+	if definition != nil {
+		layer, err = definition.create(c, tx)
+		if err != nil {
+			c.Abort()
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	targetCRS := sourceCRS
+	if layer.CoordinateReferenceSystem.Valid {
+		targetCRS = int(layer.CoordinateReferenceSystem.Int32)
+	}
+
+	query, err := db.Queries.Raw("insert-layer-object")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+	query = fmt.Sprintf(query, layer.TableName)
+
+	summary := shapefileImport{}
+	batch := &pgx.Batch{}
+	for idx, feature := range archive.Features {
+		key := feature.Attributes[parameters.KeyField]
+		if feature.Geometry == nil || key == nil {
+			summary.Skipped++
+		} else {
+			name := ""
+			if value := feature.Attributes[parameters.NameField]; value != nil {
+				name = fmt.Sprint(value)
+			}
+
+			additionalProperties := make(map[string]interface{}, len(feature.Attributes))
+			for attribute, value := range feature.Attributes {
+				if attribute == parameters.KeyField || attribute == parameters.NameField {
+					continue
+				}
+				additionalProperties[attribute] = value
+			}
+
+			batch.Queue(query, feature.Geometry, sourceCRS, targetCRS, fmt.Sprint(key), name, additionalProperties)
+			summary.Imported++
+		}
+
+		if batch.Len() == importBatchSize || (idx == len(archive.Features)-1 && batch.Len() > 0) {
+			err = tx.SendBatch(c, batch).Close()
+			if err != nil {
+				// the keys are either used by existing objects or used
+				// multiple times in the shapefile
+				emitWriteError(c, err)
+				return
+			}
+			batch = &pgx.Batch{}
+		}
+	}
+
+	if err = tx.Commit(c); err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	// the imported objects may change the inferred schema of an existing
+	// layer
+	forgetSchema(layer)
+
+	summary.Layer = layer
+	if definition != nil {
+		c.JSON(http.StatusCreated, summary)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// LayerChildren returns the descendants of an object which are the objects
+// whose key starts with the key of the object. The descendants are searched
+// in the child layer of the layer if one is configured and in the layer
+// itself otherwise.
+func LayerChildren(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+	key := c.Param("key")
+
+	format, ok := outputFormat(c)
+	if !ok {
+		return
+	}
+
+	parentQuery := layer.ContentQuery()
+	parentQuery.Where("key = " + parentQuery.Arg(key))
+	parentCount, ok := countObjects(c, parentQuery)
+	if !ok {
+		return
+	}
+	if parentCount == 0 {
+		c.Abort()
+		apiErrors.ErrUnknownObject.Emit(c)
+		return
+	}
+
+	childLayer := layer
+	if layer.ChildLayer.Valid {
+		childLayers, ok := resolveLayers(c, []string{uuid.UUID(layer.ChildLayer.Bytes).String()})
+		if !ok {
+			return
+		}
+		childLayer = childLayers[0]
+	}
+
+	contentQuery := childLayer.ContentQuery()
+	contentQuery.Fields = requestedFields(c)
+	contentQuery.KeyPrefix(key)
+	contentQuery.Where("key <> " + contentQuery.Arg(key))
+	if !filterBoundingBox(c, contentQuery) || !filterAttributes(c, contentQuery) || !geometryOutput(c, contentQuery) || !outputCRS(c, contentQuery, c.Query("crs")) {
+		return
+	}
+
+	writeLayerContents(c, format, contentQuery)
+}
@@ -1,31 +1,59 @@
 package routes
 
 import (
-	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/gin-gonic/gin"
 
-	"microservice/internal/db"
 	"microservice/types"
 )
 
+// filterKeyPrefix restricts the query to the objects whose key starts with
+// the prefix set in the "key_prefix" parameter.
+func filterKeyPrefix(c *gin.Context, query *types.ObjectQuery) {
+	if prefix := c.Query("key_prefix"); prefix != "" {
+		query.KeyPrefix(prefix)
+	}
+}
+
+// writeLayerContents paginates the query and writes the selected objects in
+// the format to the response.
+func writeLayerContents(c *gin.Context, format string, contentQuery *types.ObjectQuery) {
+	page, ok := paginate(c, contentQuery, isStreamed(format))
+	if !ok {
+		return
+	}
+
+	if isStreamed(format) {
+		streamObjects(c, format, contentQuery)
+		return
+	}
+
+	layerContents, ok := selectObjects(c, contentQuery)
+	if !ok {
+		return
+	}
+
+	if !setPaginationHeaders(c, page, contentQuery, layerContents) {
+		return
+	}
+
+	writeObjects(c, format, layerContents)
+}
+
 func LayerContents(c *gin.Context) {
 	layerInterface, _ := c.Get("layer")
 	layer, _ := layerInterface.(types.Layer)
 
-	query, err := layer.ContentQuery()
-	if err != nil {
-		c.Abort()
-		_ = c.Error(err)
+	format, ok := outputFormat(c)
+	if !ok {
 		return
 	}
 
-	var layerContents []types.Object
-	err = pgxscan.Select(c, db.Pool, &layerContents, query)
-	if err != nil {
-		c.Abort()
-		_ = c.Error(err)
+	contentQuery := layer.ContentQuery()
+	contentQuery.Fields = requestedFields(c)
+	filterKeyPrefix(c, contentQuery)
+	if !filterBoundingBox(c, contentQuery) || !filterAttributes(c, contentQuery) || !geometryOutput(c, contentQuery) || !outputCRS(c, contentQuery, c.Query("crs")) {
 		return
 	}
 
-	c.JSON(200, layerContents)
+	writeLayerContents(c, format, contentQuery)
 }
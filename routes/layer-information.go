@@ -9,6 +9,7 @@ import (
 func LayerInformation(c *gin.Context) {
 	layerInterface, _ := c.Get("layer")
 	layer, _ := layerInterface.(types.Layer)
+	layer.SupportedCRS = supportedCRS(layer)
 
 	c.JSON(200, layer)
 }
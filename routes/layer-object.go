@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// writeObject selects the single object matching the query and writes it in
+// the format to the response. The response carries an ETag derived from the
+// written body which allows clients to poll the object using conditional
+// requests.
+func writeObject(c *gin.Context, contentQuery *types.ObjectQuery) {
+	format, ok := outputFormat(c)
+	if !ok {
+		return
+	}
+
+	// a single object can not be streamed
+	if isStreamed(format) {
+		c.Abort()
+		apiErrors.ErrUnsupportedOutputFormat.Emit(c)
+		return
+	}
+
+	contentQuery.Fields = requestedFields(c)
+	if !geometryOutput(c, contentQuery) || !outputCRS(c, contentQuery, c.Query("crs")) {
+		return
+	}
+	contentQuery.Limit = 1
+
+	objects, ok := selectObjects(c, contentQuery)
+	if !ok {
+		return
+	}
+
+	if len(objects) == 0 {
+		c.Abort()
+		apiErrors.ErrUnknownObject.Emit(c)
+		return
+	}
+
+	var body []byte
+	var err error
+	contentType := binding.MIMEJSON
+	if format == formatGeoJSON {
+		contentType = mimeGeoJSON
+		body, err = json.Marshal(objects[0].Feature())
+	} else {
+		body, err = json.Marshal(objects[0])
+	}
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// etagMatches checks if the If-None-Match header contains the ETag using the
+// weak comparison required for the header.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// LayerObject returns the object of the layer with the key.
+func LayerObject(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	writeObject(c, layer.FilteredContentQuery(c.Param("key")))
+}
+
+// LayerObjectByID returns the object of the layer with the numeric id.
+func LayerObjectByID(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	contentQuery := layer.ContentQuery()
+	contentQuery.Where("id = " + contentQuery.Arg(id))
+	writeObject(c, contentQuery)
+}
@@ -26,5 +26,9 @@ func LayerOverview(c *gin.Context) {
 		return
 	}
 
+	for idx := range layers {
+		layers[idx].SupportedCRS = supportedCRS(layers[idx])
+	}
+
 	c.JSON(http.StatusOK, layers)
 }
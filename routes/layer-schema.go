@@ -0,0 +1,258 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+const mimeJSONSchema = "application/schema+json"
+
+// maxEnumValues is the maximal number of distinct values of a property for
+// which the values are listed as enum in the inferred schema.
+const maxEnumValues = 10
+
+// schemaCacheDuration is the time an inferred schema is reused before the
+// layer is scanned again.
+const schemaCacheDuration = 15 * time.Minute
+
+// cachedSchema is an inferred schema stored in the schemaCache.
+type cachedSchema struct {
+	schema  types.JSONSchema
+	expires time.Time
+}
+
+// schemaCache contains the inferred schemas of the layers by their table
+// name as inferring a schema requires scanning all objects of the layer.
+var schemaCache = struct {
+	sync.Mutex
+	schemas map[string]cachedSchema
+}{schemas: make(map[string]cachedSchema)}
+
+// propertyStatistics collects the rows of the schema inference query
+// belonging to a single property.
+type propertyStatistics struct {
+	types  []string
+	count  int
+	null   bool
+	values []json.RawMessage
+}
+
+// inferSchema scans the additional properties of all objects of the layer and
+// derives a schema from the JSON types used by each property.
+// Properties set on every object are required and properties with only few
+// distinct values of a single type are limited to these values.
+func inferSchema(ctx context.Context, layer types.Layer) (types.JSONSchema, error) {
+	query, err := db.Queries.Raw("count-layer-contents")
+	if err != nil {
+		return types.JSONSchema{}, err
+	}
+
+	var total int
+	err = db.Pool.QueryRow(ctx, fmt.Sprintf(query, layer.TableName)).Scan(&total)
+	if err != nil {
+		return types.JSONSchema{}, err
+	}
+
+	query, err = db.Queries.Raw("infer-layer-schema")
+	if err != nil {
+		return types.JSONSchema{}, err
+	}
+
+	rows, err := db.Pool.Query(ctx, fmt.Sprintf(query, layer.TableName), maxEnumValues)
+	if err != nil {
+		return types.JSONSchema{}, err
+	}
+	defer rows.Close()
+
+	properties := make(map[string]*propertyStatistics)
+	for rows.Next() {
+		var name, jsonType string
+		var count, distinctCount int
+		var values json.RawMessage
+		if err := rows.Scan(&name, &jsonType, &count, &distinctCount, &values); err != nil {
+			return types.JSONSchema{}, err
+		}
+
+		property := properties[name]
+		if property == nil {
+			property = &propertyStatistics{}
+			properties[name] = property
+		}
+
+		if jsonType == "null" {
+			property.null = true
+			continue
+		}
+
+		property.types = append(property.types, jsonType)
+		property.count += count
+		if values != nil && distinctCount < count && (jsonType == "string" || jsonType == "number") {
+			if err := json.Unmarshal(values, &property.values); err != nil {
+				return types.JSONSchema{}, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return types.JSONSchema{}, err
+	}
+
+	schema := types.JSONSchema{
+		Schema:     types.JSONSchemaDialect,
+		Title:      layer.Name,
+		Type:       []string{"object"},
+		Properties: make(map[string]*types.JSONSchema, len(properties)),
+	}
+	for name, property := range properties {
+		slices.Sort(property.types)
+		propertySchema := &types.JSONSchema{Type: property.types}
+		if len(property.types) == 1 {
+			propertySchema.Enum = property.values
+		}
+		if property.null {
+			propertySchema.Type = append(propertySchema.Type, "null")
+			if propertySchema.Enum != nil {
+				propertySchema.Enum = append(propertySchema.Enum, json.RawMessage("null"))
+			}
+		}
+		schema.Properties[name] = propertySchema
+
+		if property.count == total && !property.null {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	slices.Sort(schema.Required)
+
+	return schema, nil
+}
+
+// layerSchema returns the inferred schema of the layer which is inferred
+// again if it is not cached or the cached schema has expired.
+func layerSchema(ctx context.Context, layer types.Layer) (types.JSONSchema, error) {
+	schemaCache.Lock()
+	cached, isCached := schemaCache.schemas[layer.TableName]
+	schemaCache.Unlock()
+	if isCached && time.Now().Before(cached.expires) {
+		return cached.schema, nil
+	}
+
+	schema, err := inferSchema(ctx, layer)
+	if err != nil {
+		return types.JSONSchema{}, err
+	}
+
+	schemaCache.Lock()
+	schemaCache.schemas[layer.TableName] = cachedSchema{schema: schema, expires: time.Now().Add(schemaCacheDuration)}
+	schemaCache.Unlock()
+	return schema, nil
+}
+
+// forgetSchema removes the inferred schema of the layer from the cache after
+// the objects of the layer have been modified.
+func forgetSchema(layer types.Layer) {
+	schemaCache.Lock()
+	delete(schemaCache.schemas, layer.TableName)
+	schemaCache.Unlock()
+}
+
+func LayerSchema(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	if len(layer.Schema) > 0 {
+		c.Data(http.StatusOK, mimeJSONSchema, layer.Schema)
+		return
+	}
+
+	schema, err := layerSchema(c, layer)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", mimeJSONSchema)
+	c.JSON(http.StatusOK, schema)
+}
+
+func PinLayerSchema(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	var schema map[string]interface{}
+	err = json.Unmarshal(body, &schema)
+	if err == nil && schema == nil {
+		err = errors.New("schema must be a JSON object")
+	}
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidSchema
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	var compactSchema bytes.Buffer
+	if err := json.Compact(&compactSchema, body); err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	query, err := db.Queries.Raw("pin-layer-schema")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	_, err = db.Pool.Exec(c, query, layer.ID, compactSchema.Bytes())
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	c.Data(http.StatusOK, mimeJSONSchema, compactSchema.Bytes())
+}
+
+func UnpinLayerSchema(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	query, err := db.Queries.Raw("pin-layer-schema")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	_, err = db.Pool.Exec(c, query, layer.ID, nil)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,126 @@
+package routes_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"microservice/internal/config"
+	"microservice/internal/db"
+	"microservice/middlewares"
+	"microservice/routes"
+)
+
+func Test_LayerSchema(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.GET("/:layerID/schema", middlewares.ResolveLayer, routes.LayerSchema)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/1e694f36-cf68-426a-b6a3-7660163b03e6/schema", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpRequestResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_PinLayerSchema_InvalidSchema(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.PUT("/:layerID/schema", middlewares.ResolveLayer, routes.PinLayerSchema)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/1e694f36-cf68-426a-b6a3-7660163b03e6/schema", strings.NewReader(`["depth"]`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_PinLayerSchema(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.GET("/:layerID/schema", middlewares.ResolveLayer, routes.LayerSchema)
+	router.PUT("/:layerID/schema", middlewares.ResolveLayer, routes.PinLayerSchema)
+	router.DELETE("/:layerID/schema", middlewares.ResolveLayer, routes.UnpinLayerSchema)
+
+	t.Cleanup(func() {
+		_, _ = db.Pool.Exec(context.Background(), `UPDATE geodata.layers SET schema = NULL WHERE id = '1e694f36-cf68-426a-b6a3-7660163b03e6'`)
+	})
+
+	schema := `{"type":"object","properties":{"depth":{"type":"number"}}}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/1e694f36-cf68-426a-b6a3-7660163b03e6/schema", strings.NewReader(schema))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+		}
+	}
+
+	// the pinned schema replaces the inferred one
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/1e694f36-cf68-426a-b6a3-7660163b03e6/schema", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, schema, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/1e694f36-cf68-426a-b6a3-7660163b03e6/schema", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+}
@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"microservice/internal/db"
+	"microservice/types"
+)
+
+// countGroups executes the named query on the layer which is required to
+// return a name and a count per row and collects the rows into a map.
+func countGroups(ctx context.Context, queryName string, layer types.Layer) (map[string]int, error) {
+	query, err := db.Queries.Raw(queryName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Pool.Query(ctx, fmt.Sprintf(query, layer.TableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, err
+		}
+		counts[name] = count
+	}
+	return counts, rows.Err()
+}
+
+func LayerStatistics(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	query, err := db.Queries.Raw("get-layer-statistics")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	var statistics types.LayerStatistics
+	var native, wgs84 [4]pgtype.Float8
+	err = db.Pool.QueryRow(c, fmt.Sprintf(query, layer.TableName)).Scan(
+		&statistics.FeatureCount, &statistics.UnnamedCount,
+		&native[0], &native[1], &native[2], &native[3],
+		&wgs84[0], &wgs84[1], &wgs84[2], &wgs84[3],
+	)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	if native[0].Valid {
+		statistics.Extent = &types.LayerExtent{CRS: layer.SRID()}
+		for idx := range native {
+			statistics.Extent.Native[idx] = native[idx].Float64
+			statistics.Extent.WGS84[idx] = wgs84[idx].Float64
+		}
+	}
+
+	statistics.GeometryTypes, err = countGroups(c, "get-layer-geometry-types", layer)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	statistics.Attributes, err = countGroups(c, "get-layer-attribute-counts", layer)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statistics)
+}
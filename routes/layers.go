@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"context"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// lookupLayer resolves a layer by its UUID or its key. If the layer does not
+// exist, the error returned by the database is returned which may be checked
+// using [pgxscan.NotFound].
+func lookupLayer(ctx context.Context, reference string) (types.Layer, error) {
+	query, err := db.Queries.Raw("get-layer")
+	if err != nil {
+		return types.Layer{}, err
+	}
+
+	if err = uuid.Validate(reference); err != nil {
+		query, err = db.Queries.Raw("get-layer-by-url-key")
+		if err != nil {
+			return types.Layer{}, err
+		}
+	}
+
+	var layer types.Layer
+	err = pgxscan.Get(ctx, db.Pool, &layer, query, reference)
+	return layer, err
+}
+
+// layerReferences returns the layer references set in the "layer" parameter.
+// The parameter may be repeated or contain a comma-separated list.
+func layerReferences(c *gin.Context) []string {
+	var references []string
+	for _, value := range c.QueryArray("layer") {
+		for _, reference := range strings.Split(value, ",") {
+			if reference = strings.TrimSpace(reference); reference != "" {
+				references = append(references, reference)
+			}
+		}
+	}
+	return references
+}
+
+// resolveLayers resolves the referenced layers.
+// If a layer is unknown or not accessible, the matching error is emitted and
+// false is returned.
+func resolveLayers(c *gin.Context, references []string) ([]types.Layer, bool) {
+	layers := make([]types.Layer, len(references))
+	for idx, reference := range references {
+		layer, err := lookupLayer(c, reference)
+		if err != nil {
+			c.Abort()
+			if pgxscan.NotFound(err) {
+				apiErrors.ErrUnknownLayer.Emit(c)
+				return nil, false
+			}
+			_ = c.Error(err)
+			return nil, false
+		}
+
+		if layer.Private && !c.GetBool("AccessPrivateLayers") {
+			c.Abort()
+			apiErrors.ErrLayerPrivate.Emit(c)
+			return nil, false
+		}
+		layers[idx] = layer
+	}
+
+	return layers, true
+}
+
+// requestedLayers returns the layers referenced in the "layer" parameter or
+// all accessible layers if the parameter is not set.
+// If the layers cannot be resolved, the matching error is emitted and false
+// is returned.
+func requestedLayers(c *gin.Context) ([]types.Layer, bool) {
+	if references := layerReferences(c); len(references) > 0 {
+		return resolveLayers(c, references)
+	}
+	return accessibleLayers(c)
+}
@@ -0,0 +1,490 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// featureInput is a GeoJSON Feature sent to create or modify an object.
+// The key and the name of the object are read from the properties of the
+// feature while all other properties are stored as additional properties.
+type featureInput struct {
+	Type       string                 `json:"type"`
+	Geometry   json.RawMessage        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+
+	// geometry contains the decoded geometry which is nil if the feature
+	// does not contain a geometry
+	geometry geom.T
+}
+
+// decodeGeometry decodes the GeoJSON geometry of the feature if it is set.
+func (f *featureInput) decodeGeometry() error {
+	if len(f.Geometry) == 0 || string(f.Geometry) == "null" {
+		return nil
+	}
+	return geojson.Unmarshal(f.Geometry, &f.geometry)
+}
+
+// key returns the key set in the properties of the feature or the fallback
+// if the feature does not set a key.
+func (f featureInput) key(fallback string) (string, error) {
+	value, isSet := f.Properties["key"]
+	if !isSet {
+		return fallback, nil
+	}
+	key, isString := value.(string)
+	if !isString || key == "" {
+		return "", errors.New("the key needs to be a non-empty string")
+	}
+	return key, nil
+}
+
+// name returns the name set in the properties of the feature and if the
+// feature sets a name. A name set to null is returned as empty name.
+func (f featureInput) name() (string, bool, error) {
+	value, isSet := f.Properties["name"]
+	if !isSet || value == nil {
+		return "", isSet, nil
+	}
+	name, isString := value.(string)
+	if !isString {
+		return "", false, errors.New("the name needs to be a string")
+	}
+	return name, true, nil
+}
+
+// additionalProperties returns the properties of the feature except the key
+// and the name.
+func (f featureInput) additionalProperties() map[string]interface{} {
+	properties := make(map[string]interface{}, len(f.Properties))
+	for property, value := range f.Properties {
+		if property == "key" || property == "name" {
+			continue
+		}
+		properties[property] = value
+	}
+	return properties
+}
+
+// bindFeature reads the GeoJSON Feature from the request body and decodes its
+// geometry. If requireGeometry is set, features without a geometry are
+// rejected.
+// If the feature is invalid, the matching error is emitted and false is
+// returned.
+func bindFeature(c *gin.Context, requireGeometry bool) (featureInput, bool) {
+	var feature featureInput
+	err := c.ShouldBindJSON(&feature)
+	if err == nil && feature.Type != "Feature" {
+		err = errors.New("the type of the feature needs to be 'Feature'")
+	}
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidFeature
+		res.Errors = []error{err}
+		res.Emit(c)
+		return featureInput{}, false
+	}
+
+	if err := feature.decodeGeometry(); err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidGeometry
+		res.Errors = []error{err}
+		res.Emit(c)
+		return featureInput{}, false
+	}
+
+	if requireGeometry && feature.geometry == nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidGeometry
+		res.Errors = []error{errors.New("geometry must not be null")}
+		res.Emit(c)
+		return featureInput{}, false
+	}
+
+	return feature, true
+}
+
+// validateGeometry checks that the geometry is valid according to the OGC
+// Simple Features specification as invalid geometries break the spatial
+// queries of the layer.
+// If the geometry is invalid, the matching error is emitted and false is
+// returned.
+func validateGeometry(c *gin.Context, geometry geom.T) bool {
+	if geometry == nil {
+		return true
+	}
+
+	query, err := db.Queries.Raw("validate-geometry")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	var valid bool
+	var reason pgtype.Text
+	err = db.Pool.QueryRow(c, query, geometry).Scan(&valid, &reason)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	if !valid {
+		c.Abort()
+		res := apiErrors.ErrInvalidGeometry
+		res.Errors = []error{fmt.Errorf("invalid geometry: %s", reason.String)}
+		res.Emit(c)
+		return false
+	}
+	return true
+}
+
+// inputCRS resolves the coordinate reference system of the submitted
+// geometry which is set in the "crs" parameter. WGS 84 is used if the
+// parameter is not set as required by GeoJSON.
+// If the coordinate reference system is unknown, the matching error is
+// emitted and false is returned.
+func inputCRS(c *gin.Context) (int, bool) {
+	if reference := c.Query("crs"); reference != "" {
+		return resolveCRS(c, reference)
+	}
+	return 4326, true
+}
+
+// objectExists checks if the layer contains an object with the key.
+func objectExists(c *gin.Context, tx pgx.Tx, layer types.Layer, key string) (bool, error) {
+	query, err := db.Queries.Raw("layer-object-exists")
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = tx.QueryRow(c, fmt.Sprintf(query, layer.TableName), key).Scan(&exists)
+	return exists, err
+}
+
+// modifyObject executes the modification in a transaction. The modification
+// emits its errors itself and returns false to roll back the transaction.
+// If the transaction fails, the matching error is emitted and false is
+// returned.
+func modifyObject(c *gin.Context, layer types.Layer, modification func(tx pgx.Tx) bool) bool {
+	tx, err := db.Pool.Begin(c)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+	defer func() {
+		_ = tx.Rollback(c)
+	}()
+
+	if !modification(tx) {
+		return false
+	}
+
+	if err := tx.Commit(c); err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	forgetSchema(layer)
+	return true
+}
+
+// ensureKeyAvailable checks that the layer does not contain an object with
+// the key. Concurrent requests may still claim the key afterwards, which is
+// rejected by the unique constraint on the keys of the layer.
+// If the key is used or cannot be checked, the matching error is emitted and
+// false is returned.
+func ensureKeyAvailable(c *gin.Context, tx pgx.Tx, layer types.Layer, key string) bool {
+	exists, err := objectExists(c, tx, layer, key)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	if exists {
+		c.Abort()
+		apiErrors.ErrObjectKeyExists.Emit(c)
+		return false
+	}
+	return true
+}
+
+// emitWriteError emits the error returned while writing an object. Keys used
+// by another object are reported as such.
+func emitWriteError(c *gin.Context, err error) {
+	c.Abort()
+	if isUniqueViolation(err) {
+		apiErrors.ErrObjectKeyExists.Emit(c)
+		return
+	}
+	_ = c.Error(err)
+}
+
+// writeModifiedObject writes the object with the key to the response using
+// the coordinate reference system of the submitted geometry.
+func writeModifiedObject(c *gin.Context, layer types.Layer, key string, crs int, status int) {
+	contentQuery := layer.FilteredContentQuery(key)
+	contentQuery.CRS = crs
+	contentQuery.Limit = 1
+
+	objects, ok := selectObjects(c, contentQuery)
+	if !ok {
+		return
+	}
+
+	if len(objects) == 0 {
+		c.Abort()
+		apiErrors.ErrUnknownObject.Emit(c)
+		return
+	}
+
+	c.Header("Content-Crs", "<"+crsURI(crs)+">")
+	c.JSON(status, objects[0])
+}
+
+// modificationParameters reads the feature from the request body and the
+// coordinate reference system of its geometry and validates the geometry.
+// If a parameter is invalid, the matching error is emitted and false is
+// returned.
+func modificationParameters(c *gin.Context, requireGeometry bool) (featureInput, int, bool) {
+	feature, ok := bindFeature(c, requireGeometry)
+	if !ok {
+		return featureInput{}, 0, false
+	}
+
+	crs, ok := inputCRS(c)
+	if !ok {
+		return featureInput{}, 0, false
+	}
+
+	if !validateGeometry(c, feature.geometry) {
+		return featureInput{}, 0, false
+	}
+
+	return feature, crs, true
+}
+
+// CreateObject creates a new object with the key in the layer from the
+// GeoJSON Feature contained in the request body.
+func CreateObject(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+	key := c.Param("key")
+
+	feature, crs, ok := modificationParameters(c, true)
+	if !ok {
+		return
+	}
+
+	featureKey, err := feature.key(key)
+	if err == nil && featureKey != key {
+		err = errors.New("the key of the feature does not match the key of the object")
+	}
+	name, _, nameErr := feature.name()
+	if err == nil {
+		err = nameErr
+	}
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidFeature
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	query, err := db.Queries.Raw("insert-layer-object")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	ok = modifyObject(c, layer, func(tx pgx.Tx) bool {
+		if !ensureKeyAvailable(c, tx, layer, key) {
+			return false
+		}
+
+		_, err := tx.Exec(c, fmt.Sprintf(query, layer.TableName),
+			feature.geometry, crs, layer.SRID(), key, name, feature.additionalProperties())
+		if err != nil {
+			emitWriteError(c, err)
+			return false
+		}
+		return true
+	})
+	if !ok {
+		return
+	}
+
+	writeModifiedObject(c, layer, key, crs, http.StatusCreated)
+}
+
+// updateObject executes the named update query after checking that the new
+// key of the object is not used by another object. The query is required to
+// accept the key of the object as last argument.
+// If the object does not exist or cannot be updated, the matching error is
+// emitted and false is returned.
+func updateObject(c *gin.Context, layer types.Layer, key string, newKey string, queryName string, args ...interface{}) bool {
+	query, err := db.Queries.Raw(queryName)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return false
+	}
+
+	return modifyObject(c, layer, func(tx pgx.Tx) bool {
+		if newKey != key && !ensureKeyAvailable(c, tx, layer, newKey) {
+			return false
+		}
+
+		tag, err := tx.Exec(c, fmt.Sprintf(query, layer.TableName), append(args, key)...)
+		if err != nil {
+			emitWriteError(c, err)
+			return false
+		}
+
+		if tag.RowsAffected() == 0 {
+			c.Abort()
+			apiErrors.ErrUnknownObject.Emit(c)
+			return false
+		}
+		return true
+	})
+}
+
+// ReplaceObject replaces the object with the key by the GeoJSON Feature
+// contained in the request body. The object is renamed if the feature
+// contains another key.
+func ReplaceObject(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+	key := c.Param("key")
+
+	feature, crs, ok := modificationParameters(c, true)
+	if !ok {
+		return
+	}
+
+	newKey, err := feature.key(key)
+	name, _, nameErr := feature.name()
+	if err == nil {
+		err = nameErr
+	}
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidFeature
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	if !updateObject(c, layer, key, newKey, "replace-layer-object",
+		feature.geometry, crs, layer.SRID(), newKey, name, feature.additionalProperties()) {
+		return
+	}
+
+	writeModifiedObject(c, layer, newKey, crs, http.StatusOK)
+}
+
+// UpdateObject merges the GeoJSON Feature contained in the request body into
+// the object with the key. The geometry and the name are only replaced if
+// they are set in the feature and additional properties set to null are
+// removed from the object.
+func UpdateObject(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+	key := c.Param("key")
+
+	feature, crs, ok := modificationParameters(c, false)
+	if !ok {
+		return
+	}
+
+	newKey, err := feature.key(key)
+	name, nameSet, nameErr := feature.name()
+	if err == nil {
+		err = nameErr
+	}
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidFeature
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	// the name is only replaced if it is set in the feature
+	var newName *string
+	if nameSet {
+		newName = &name
+	}
+
+	properties := feature.additionalProperties()
+	removedProperties := []string{}
+	for property, value := range properties {
+		if value == nil {
+			removedProperties = append(removedProperties, property)
+			delete(properties, property)
+		}
+	}
+
+	if !updateObject(c, layer, key, newKey, "patch-layer-object",
+		feature.geometry, crs, layer.SRID(), newKey, newName, properties, removedProperties) {
+		return
+	}
+
+	writeModifiedObject(c, layer, newKey, crs, http.StatusOK)
+}
+
+// DeleteObject deletes the object with the key from the layer.
+func DeleteObject(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+	key := c.Param("key")
+
+	query, err := db.Queries.Raw("delete-layer-object")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	ok := modifyObject(c, layer, func(tx pgx.Tx) bool {
+		tag, err := tx.Exec(c, fmt.Sprintf(query, layer.TableName), key)
+		if err != nil {
+			c.Abort()
+			_ = c.Error(err)
+			return false
+		}
+
+		if tag.RowsAffected() == 0 {
+			c.Abort()
+			apiErrors.ErrUnknownObject.Emit(c)
+			return false
+		}
+		return true
+	})
+	if !ok {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,157 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"microservice/internal/config"
+	"microservice/middlewares"
+	"microservice/routes"
+)
+
+func Test_ModifyObject(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/:key", middlewares.ResolveLayer, routes.CreateObject)
+	router.PATCH("/content/:layerID/:key", middlewares.ResolveLayer, routes.UpdateObject)
+	router.DELETE("/content/:layerID/:key", middlewares.ResolveLayer, routes.DeleteObject)
+
+	url := fmt.Sprintf("/content/e517edaa-8d7b-4f10-9cfc-56a7c56109f0/test_object_%d", time.Now().UnixNano())
+	feature := `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [8.21, 53.14]}, "properties": {"name": "Test Object", "population": 1}}`
+
+	steps := []struct {
+		method string
+		body   string
+		status int
+	}{
+		{"POST", feature, http.StatusCreated},
+		// creating the object a second time needs to be rejected
+		{"POST", feature, http.StatusConflict},
+		{"PATCH", `{"type": "Feature", "properties": {"name": "Renamed Object", "population": null}}`, http.StatusOK},
+		{"DELETE", "", http.StatusNoContent},
+		{"DELETE", "", http.StatusNotFound},
+	}
+
+	for _, step := range steps {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(step.method, url, strings.NewReader(step.body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, step.status, w.Code)
+		if t.Failed() {
+			t.Log(w.Body.String())
+		}
+
+		valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+		if !valid {
+			t.Fail()
+			for _, e := range validationErrors {
+				t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+				if e.SchemaValidationErrors != nil {
+					t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+						e.SchemaValidationErrors[0].Reason,
+						e.SchemaValidationErrors[0].Line,
+						e.SchemaValidationErrors[0].Column)
+				}
+			}
+		}
+	}
+}
+
+func Test_CreateObject_InvalidGeometry(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/:key", middlewares.ResolveLayer, routes.CreateObject)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/content/e517edaa-8d7b-4f10-9cfc-56a7c56109f0/invalid_object", strings.NewReader(`{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 1], [1, 0], [0, 1], [0, 0]]]}, "properties": {}}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_CreateObject_InvalidFeature(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/:key", middlewares.ResolveLayer, routes.CreateObject)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/content/e517edaa-8d7b-4f10-9cfc-56a7c56109f0/invalid_object", strings.NewReader(`{"type": "FeatureCollection", "features": []}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_ReplaceObject_UnknownObject(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.PUT("/content/:layerID/:key", middlewares.ResolveLayer, routes.ReplaceObject)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/content/e517edaa-8d7b-4f10-9cfc-56a7c56109f0/unknown-key", strings.NewReader(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [8.21, 53.14]}, "properties": {"name": "Unknown Object"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"microservice/internal/db"
+	"microservice/types"
+)
+
+// recordSeparator is prepended to every feature in a GeoJSON text sequence
+// as required by RFC 8142.
+const recordSeparator = 0x1E
+
+// flushInterval is the number of features after which a streamed response
+// is flushed to the client.
+const flushInterval = 100
+
+// selectObjects executes the query and returns the selected objects.
+// If the query fails, the error is set on the context and false is returned.
+func selectObjects(c *gin.Context, contentQuery *types.ObjectQuery) ([]types.Object, bool) {
+	query, err := contentQuery.SQL()
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	var objects []types.Object
+	err = pgxscan.Select(c, db.Pool, &objects, query, contentQuery.Args()...)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	for idx := range objects {
+		objects[idx] = contentQuery.ApplyOutput(objects[idx])
+	}
+	return objects, true
+}
+
+// streamObjects executes the query and writes every selected object as a
+// GeoJSON feature to the response as soon as it has been read from the
+// database. This keeps the memory usage independent of the number of objects.
+// The query is bound to the request's context and is therefore cancelled if
+// the client disconnects.
+func streamObjects(c *gin.Context, format string, contentQuery *types.ObjectQuery) {
+	query, err := contentQuery.SQL()
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	rows, err := db.Pool.Query(ctx, query, contentQuery.Args()...)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+	defer rows.Close()
+
+	// errors of the query are only reported after reading the first row.
+	// therefore, the status is only written afterward to still be able to
+	// respond with an error
+	hasRows := rows.Next()
+	if err := rows.Err(); err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", streamingContentTypes[format])
+	c.Status(http.StatusOK)
+
+	scanner := pgxscan.NewRowScanner(rows)
+	encoder := json.NewEncoder(c.Writer)
+	for count := 1; hasRows; count++ {
+		var object types.Object
+		if err := scanner.Scan(&object); err != nil {
+			log.Error().Err(err).Msg("unable to scan streamed object")
+			return
+		}
+
+		if format == formatGeoJSONSeq {
+			_, _ = c.Writer.Write([]byte{recordSeparator})
+		}
+		if err := encoder.Encode(contentQuery.ApplyOutput(object).Feature()); err != nil {
+			// the client is not reading the response anymore
+			return
+		}
+
+		if count%flushInterval == 0 {
+			c.Writer.Flush()
+		}
+		hasRows = rows.Next()
+	}
+
+	if err := rows.Err(); err != nil && ctx.Err() == nil {
+		log.Error().Err(err).Msg("unable to stream objects")
+	}
+	c.Writer.Flush()
+}
@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"microservice/internal/db"
+	"microservice/types"
+)
+
+// collection converts the layer into its OGC API - Features representation.
+func collection(baseURL string, layer types.Layer) types.Collection {
+	collectionURL := fmt.Sprintf("%s/collections/%s", baseURL, layer.TableName)
+
+	return types.Collection{
+		ID:          layer.TableName,
+		Title:       layer.Name,
+		Description: layer.Description.String,
+		Attribution: layer.Attribution.String,
+		Links: []types.Link{
+			{Href: collectionURL, Rel: "self", Type: "application/json", Title: "This collection"},
+			{Href: collectionURL + "/items", Rel: "items", Type: mimeGeoJSON, Title: "The objects of the layer"},
+		},
+		ItemType:   "feature",
+		CRS:        supportedCRS(layer),
+		StorageCRS: crsURI(layer.SRID()),
+	}
+}
+
+// layerExtent returns the bounding box of all objects of the layer in WGS 84.
+// If the layer does not contain any objects, false is returned.
+func layerExtent(ctx context.Context, layer types.Layer) (types.Extent, bool, error) {
+	query, err := db.Queries.Raw("get-layer-extent")
+	if err != nil {
+		return types.Extent{}, false, err
+	}
+
+	var minX, minY, maxX, maxY pgtype.Float8
+	err = db.Pool.QueryRow(ctx, fmt.Sprintf(query, layer.TableName)).Scan(&minX, &minY, &maxX, &maxY)
+	if err != nil {
+		return types.Extent{}, false, err
+	}
+
+	if !minX.Valid {
+		return types.Extent{}, false, nil
+	}
+
+	return types.Extent{
+		Spatial: types.SpatialExtent{
+			BBox: [][4]float64{{minX.Float64, minY.Float64, maxX.Float64, maxY.Float64}},
+			CRS:  crs84,
+		},
+	}, true, nil
+}
+
+func OGCCollections(c *gin.Context) {
+	query, err := db.Queries.Raw("get-layers")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	var layers []types.Layer
+	err = pgxscan.Select(c, db.Pool, &layers, query, c.GetBool("AccessPrivateLayers"))
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	baseURL := ogcBaseURL(c)
+	collections := types.Collections{
+		Links: []types.Link{
+			{Href: baseURL + "/collections", Rel: "self", Type: "application/json", Title: "This document"},
+		},
+		Collections: make([]types.Collection, len(layers)),
+	}
+	for idx, layer := range layers {
+		collections.Collections[idx] = collection(baseURL, layer)
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+func OGCCollection(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	extent, hasExtent, err := layerExtent(c, layer)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	response := collection(ogcBaseURL(c), layer)
+	if hasExtent {
+		response.Extent = &extent
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,143 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// ogcDefaultLimit is the number of features returned if the client did not
+// set a limit.
+const ogcDefaultLimit = 10
+
+// ogcItemsParameters contains the query parameters of the items endpoint.
+// The bounding box and the filter are read separately.
+type ogcItemsParameters struct {
+	Limit  int    `binding:"omitempty,min=1,max=10000" form:"limit"`
+	Offset int    `binding:"min=0"                     form:"offset"`
+	CRS    string `form:"crs"`
+}
+
+// itemsPage returns the URL of the items endpoint selecting the page at the
+// offset while keeping all other parameters of the request.
+func itemsPage(c *gin.Context, itemsURL string, offset int) string {
+	parameters := c.Request.URL.Query()
+	parameters.Set("offset", strconv.Itoa(offset))
+	return itemsURL + "?" + parameters.Encode()
+}
+
+func OGCItems(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	var parameters ogcItemsParameters
+	if err := c.ShouldBindQuery(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+	if parameters.Limit == 0 {
+		parameters.Limit = ogcDefaultLimit
+	}
+
+	contentQuery := layer.ContentQuery()
+	contentQuery.Fields = requestedFields(c)
+	if !filterBoundingBox(c, contentQuery) || !filterAttributes(c, contentQuery) || !geometryOutput(c, contentQuery) {
+		return
+	}
+	if !outputCRS(c, contentQuery, parameters.CRS) {
+		return
+	}
+	contentQuery.Limit = parameters.Limit
+	contentQuery.Offset = parameters.Offset
+
+	objects, ok := selectObjects(c, contentQuery)
+	if !ok {
+		return
+	}
+
+	numberMatched, ok := countObjects(c, contentQuery)
+	if !ok {
+		return
+	}
+
+	collectionURL := fmt.Sprintf("%s/collections/%s", ogcBaseURL(c), layer.TableName)
+	itemsURL := collectionURL + "/items"
+	selfURL := itemsURL
+	if c.Request.URL.RawQuery != "" {
+		selfURL += "?" + c.Request.URL.RawQuery
+	}
+
+	response := types.ItemCollection{
+		FeatureCollection: types.NewFeatureCollection(objects),
+		Links: []types.Link{
+			{Href: selfURL, Rel: "self", Type: mimeGeoJSON, Title: "This document"},
+			{Href: collectionURL, Rel: "collection", Type: "application/json", Title: "The collection"},
+		},
+		TimeStamp:      time.Now().UTC(),
+		NumberMatched:  numberMatched,
+		NumberReturned: len(objects),
+	}
+
+	if parameters.Offset+len(objects) < numberMatched {
+		response.Links = append(response.Links, types.Link{
+			Href: itemsPage(c, itemsURL, parameters.Offset+len(objects)), Rel: "next", Type: mimeGeoJSON, Title: "Next page",
+		})
+	}
+	if parameters.Offset > 0 {
+		response.Links = append(response.Links, types.Link{
+			Href: itemsPage(c, itemsURL, max(parameters.Offset-parameters.Limit, 0)), Rel: "prev", Type: mimeGeoJSON, Title: "Previous page",
+		})
+	}
+
+	c.Header("Content-Type", mimeGeoJSON)
+	c.JSON(http.StatusOK, response)
+}
+
+func OGCItem(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	featureID, err := strconv.ParseUint(c.Param("featureId"), 10, 64)
+	if err != nil {
+		c.Abort()
+		apiErrors.ErrUnknownFeature.Emit(c)
+		return
+	}
+
+	contentQuery := layer.ContentQuery()
+	contentQuery.Fields = requestedFields(c)
+	contentQuery.Where("id = " + contentQuery.Arg(featureID))
+	if !geometryOutput(c, contentQuery) || !outputCRS(c, contentQuery, c.Query("crs")) {
+		return
+	}
+
+	objects, ok := selectObjects(c, contentQuery)
+	if !ok {
+		return
+	}
+
+	if len(objects) == 0 {
+		c.Abort()
+		apiErrors.ErrUnknownFeature.Emit(c)
+		return
+	}
+
+	collectionURL := fmt.Sprintf("%s/collections/%s", ogcBaseURL(c), layer.TableName)
+	feature := objects[0].Feature()
+	feature.Links = []types.Link{
+		{Href: fmt.Sprintf("%s/items/%d", collectionURL, featureID), Rel: "self", Type: mimeGeoJSON, Title: "This feature"},
+		{Href: collectionURL, Rel: "collection", Type: "application/json", Title: "The collection"},
+	}
+
+	c.Header("Content-Type", mimeGeoJSON)
+	c.JSON(http.StatusOK, feature)
+}
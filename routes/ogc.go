@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"microservice/types"
+)
+
+// OpenAPIDocument contains the OpenAPI definition of the service which is
+// linked as service description by the OGC API - Features landing page.
+// It is set by the main package as the document is stored in the root of the
+// repository.
+var OpenAPIDocument []byte
+
+// The conformance classes of OGC API - Features implemented by the service.
+var conformanceClasses = []string{
+	"http://www.opengis.net/spec/ogcapi-features-1/1.0/conf/core",
+	"http://www.opengis.net/spec/ogcapi-features-1/1.0/conf/oas30",
+	"http://www.opengis.net/spec/ogcapi-features-1/1.0/conf/geojson",
+	"http://www.opengis.net/spec/ogcapi-features-2/1.0/conf/crs",
+	"http://www.opengis.net/spec/cql2/1.0/conf/basic-cql2",
+	"http://www.opengis.net/spec/cql2/1.0/conf/advanced-comparison-operators",
+	"http://www.opengis.net/spec/cql2/1.0/conf/cql2-text",
+	"http://www.opengis.net/spec/cql2/1.0/conf/cql2-json",
+}
+
+const (
+	mimeOpenAPI = "application/vnd.oai.openapi;version=3.0"
+	ogcEPSGURI  = "http://www.opengis.net/def/crs/EPSG/0/%d"
+)
+
+// serviceBaseURL returns the absolute URL of the service.
+// As the service is usually running behind a gateway, the forwarded headers
+// set by the gateway are used to build the URL if they are available.
+func serviceBaseURL(c *gin.Context) string {
+	scheme := c.GetHeader("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		}
+	}
+
+	host := c.GetHeader("X-Forwarded-Host")
+	if host == "" {
+		host = c.Request.Host
+	}
+
+	prefix := strings.TrimSuffix(c.GetHeader("X-Forwarded-Prefix"), "/")
+	return fmt.Sprintf("%s://%s%s", scheme, host, prefix)
+}
+
+// ogcBaseURL returns the absolute URL of the OGC API - Features endpoints.
+func ogcBaseURL(c *gin.Context) string {
+	return serviceBaseURL(c) + "/ogc"
+}
+
+// crsURI returns the OGC URI of the coordinate reference system. WGS 84 is
+// returned as CRS84 as the geometries always use the longitude/latitude axis
+// order.
+func crsURI(epsgCode int) string {
+	if epsgCode == 4326 {
+		return crs84
+	}
+	return fmt.Sprintf(ogcEPSGURI, epsgCode)
+}
+
+func OGCLandingPage(c *gin.Context) {
+	baseURL := ogcBaseURL(c)
+	c.JSON(http.StatusOK, types.LandingPage{
+		Title:       "Geospatial Data Service",
+		Description: "Access to the layers of geospatial data stored on the WISdoM platform",
+		Links: []types.Link{
+			{Href: baseURL, Rel: "self", Type: "application/json", Title: "This document"},
+			{Href: baseURL + "/api", Rel: "service-desc", Type: mimeOpenAPI, Title: "The API definition"},
+			{Href: baseURL + "/conformance", Rel: "conformance", Type: "application/json", Title: "Conformance classes"},
+			{Href: baseURL + "/collections", Rel: "data", Type: "application/json", Title: "Layers"},
+		},
+	})
+}
+
+func OGCConformance(c *gin.Context) {
+	c.JSON(http.StatusOK, types.Conformance{ConformsTo: conformanceClasses})
+}
+
+func OGCAPIDefinition(c *gin.Context) {
+	c.Data(http.StatusOK, mimeOpenAPI, OpenAPIDocument)
+}
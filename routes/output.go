@@ -0,0 +1,82 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// The output formats supported by the routes returning objects.
+const (
+	formatJSON       = "json"
+	formatGeoJSON    = "geojson"
+	formatGeoJSONSeq = "geojsonseq"
+	formatNDJSON     = "ndjson"
+)
+
+const (
+	mimeGeoJSON    = "application/geo+json"
+	mimeGeoJSONSeq = "application/geo+json-seq"
+	mimeNDJSON     = "application/x-ndjson"
+)
+
+// streamingContentTypes maps the output formats that are streamed to the
+// client to their content type.
+var streamingContentTypes = map[string]string{
+	formatGeoJSONSeq: mimeGeoJSONSeq,
+	formatNDJSON:     mimeNDJSON,
+}
+
+// outputFormat determines the format used for the objects in the response.
+// The format may be selected explicitly using the "f" query parameter and is
+// negotiated using the Accept header otherwise.
+// If an unsupported format is requested, the matching error is emitted and
+// false is returned.
+func outputFormat(c *gin.Context) (string, bool) {
+	switch format := strings.ToLower(c.Query("f")); format {
+	case "":
+		break
+	case formatJSON, formatGeoJSON, formatGeoJSONSeq, formatNDJSON:
+		return format, true
+	default:
+		c.Abort()
+		apiErrors.ErrUnsupportedOutputFormat.Emit(c)
+		return "", false
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, mimeGeoJSON, mimeGeoJSONSeq, mimeNDJSON) {
+	case mimeGeoJSON:
+		return formatGeoJSON, true
+	case mimeGeoJSONSeq:
+		return formatGeoJSONSeq, true
+	case mimeNDJSON:
+		return formatNDJSON, true
+	default:
+		return formatJSON, true
+	}
+}
+
+// isStreamed checks if the objects are streamed to the client in the output
+// format instead of being written at once.
+func isStreamed(format string) bool {
+	_, streamed := streamingContentTypes[format]
+	return streamed
+}
+
+// writeObjects writes the objects in the requested output format.
+// The objects are either written as a JSON array or as a GeoJSON
+// FeatureCollection.
+func writeObjects(c *gin.Context, format string, objects []types.Object) {
+	if format == formatGeoJSON {
+		c.Header("Content-Type", mimeGeoJSON)
+		c.JSON(http.StatusOK, types.NewFeatureCollection(objects))
+		return
+	}
+
+	c.JSON(http.StatusOK, objects)
+}
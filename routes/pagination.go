@@ -0,0 +1,112 @@
+package routes
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// defaultPageSize is the number of objects returned if the client did not
+// set a limit.
+const defaultPageSize = 1000
+
+// maxPageSize is the maximal number of objects which may be requested at once.
+const maxPageSize = 10000
+
+// pagination contains the query parameters controlling which page of objects
+// is returned. The pages are either selected using an offset or using the id
+// of the last object of the previous page (keyset pagination).
+type pagination struct {
+	Limit  int    `binding:"min=0" form:"limit"`
+	Offset int    `binding:"min=0" form:"offset"`
+	After  uint64 `form:"after"`
+}
+
+// paginate reads the pagination parameters from the request and applies them
+// to the query. The default and maximum page size are not applied to
+// streamed output as the objects are written without being buffered.
+// If the parameters are invalid, the matching error is emitted and false is
+// returned.
+func paginate(c *gin.Context, query *types.ObjectQuery, streamed bool) (pagination, bool) {
+	var parameters pagination
+	if err := c.ShouldBindQuery(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return parameters, false
+	}
+
+	if !streamed && parameters.Limit > maxPageSize {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{fmt.Errorf("at most %d objects may be requested at once", maxPageSize)}
+		res.Emit(c)
+		return parameters, false
+	}
+
+	if !streamed && parameters.Limit == 0 {
+		parameters.Limit = defaultPageSize
+	}
+
+	query.Limit = parameters.Limit
+	query.Offset = parameters.Offset
+	query.After = parameters.After
+	return parameters, true
+}
+
+// countObjects returns the number of objects matching the query while
+// ignoring the pagination.
+// If the query fails, the error is set on the context and false is returned.
+func countObjects(c *gin.Context, query *types.ObjectQuery) (int, bool) {
+	countQuery, err := query.CountSQL()
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return 0, false
+	}
+
+	var total int
+	err = db.Pool.QueryRow(c, countQuery, query.Args()...).Scan(&total)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return 0, false
+	}
+	return total, true
+}
+
+// setPaginationHeaders sets the total number of objects matching the query in
+// the X-Total-Count header and links the next page in the Link header if more
+// objects are available.
+func setPaginationHeaders(c *gin.Context, parameters pagination, query *types.ObjectQuery, objects []types.Object) bool {
+	total, ok := countObjects(c, query)
+	if !ok {
+		return false
+	}
+	c.Header("X-Total-Count", strconv.Itoa(total))
+
+	if len(objects) < parameters.Limit || (parameters.After == 0 && parameters.Offset+len(objects) >= total) {
+		return true
+	}
+
+	// the next page uses the keyset pagination unless the client explicitly
+	// requested an offset based pagination
+	nextPage := c.Request.URL.Query()
+	if parameters.Offset > 0 && parameters.After == 0 {
+		nextPage.Set("offset", strconv.Itoa(parameters.Offset+len(objects)))
+	} else {
+		nextPage.Del("offset")
+		nextPage.Set("after", strconv.FormatUint(objects[len(objects)-1].ID, 10))
+	}
+
+	// the link only contains the query as the service is usually running
+	// behind a gateway changing the path of the request
+	c.Header("Link", fmt.Sprintf(`<?%s>; rel="next"`, nextPage.Encode()))
+	return true
+}
@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gin-gonic/gin"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// predicateRelationPattern matches the relation of a predicate with its
+// optional argument, e.g. "within", "dwithin(500)" or "relate(T*F**F***)".
+var predicateRelationPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?$`)
+
+// spatialPredicate requires the objects of a layer to have a spatial relation
+// to a single object of another layer.
+type spatialPredicate struct {
+	relation types.SpatialRelation
+	layer    types.Layer
+	key      string
+}
+
+// condition returns the condition checking the predicate for the objects of
+// the base layer. The object of the other layer is transformed into the
+// coordinate reference system of the base layer to allow the usage of its
+// spatial index.
+func (p spatialPredicate) condition(q *types.ObjectQuery, baseLayer types.Layer) string {
+	other := fmt.Sprintf(`(SELECT ST_Transform(geometry, %d) FROM geodata."%s" WHERE key = %s)`,
+		baseLayer.SRID(), p.layer.TableName, q.Arg(p.key))
+	return p.relation.Condition(q, other)
+}
+
+// predicateLayer resolves the layer referenced in a predicate. The layers are
+// cached as multiple predicates usually reference the same layer.
+// If the layer does not exist, the matching error is emitted and false is
+// returned.
+func predicateLayer(c *gin.Context, reference string, layers map[string]types.Layer) (types.Layer, bool) {
+	if layer, isCached := layers[reference]; isCached {
+		return layer, true
+	}
+
+	layer, err := lookupLayer(c, reference)
+	if err != nil {
+		c.Abort()
+		if pgxscan.NotFound(err) {
+			apiErrors.ErrUnknownTopLayer.Emit(c)
+			return types.Layer{}, false
+		}
+		_ = c.Error(err)
+		return types.Layer{}, false
+	}
+
+	layers[reference] = layer
+	return layer, true
+}
+
+// parsePredicate parses a predicate in the "<relation>:<layer>:<key>" format.
+// The relation may contain an argument which is the distance for "dwithin"
+// and the DE-9IM pattern for "relate".
+// If the predicate is invalid, the matching error is emitted and false is
+// returned.
+func parsePredicate(c *gin.Context, predicate string, layers map[string]types.Layer) (spatialPredicate, bool) {
+	parts := strings.SplitN(predicate, ":", 3)
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{fmt.Errorf("predicate '%s' does not match the format '<relation>:<layer>:<key>'", predicate)}
+		res.Emit(c)
+		return spatialPredicate{}, false
+	}
+
+	match := predicateRelationPattern.FindStringSubmatch(parts[0])
+	if match == nil {
+		c.Abort()
+		apiErrors.ErrUnsupportedSpatialRelation.Emit(c)
+		return spatialPredicate{}, false
+	}
+
+	var parameters relationParameters
+	if strings.EqualFold(match[1], "relate") {
+		if match[2] == "" {
+			c.Abort()
+			res := apiErrors.ErrInvalidParameter
+			res.Errors = []error{errors.New("relate predicates require a DE-9IM pattern")}
+			res.Emit(c)
+			return spatialPredicate{}, false
+		}
+		parameters.Relate = match[2]
+	} else {
+		parameters.Relation = match[1]
+		if match[2] != "" {
+			distance, err := strconv.ParseFloat(match[2], 64)
+			if err != nil || distance < 0 {
+				c.Abort()
+				res := apiErrors.ErrInvalidParameter
+				res.Errors = []error{errors.New("the distance of a predicate needs to be a non-negative number")}
+				res.Emit(c)
+				return spatialPredicate{}, false
+			}
+			parameters.Distance = &distance
+		}
+	}
+
+	relation, ok := parameters.spatialRelation(c)
+	if !ok {
+		return spatialPredicate{}, false
+	}
+
+	layer, ok := predicateLayer(c, parts[1], layers)
+	if !ok {
+		return spatialPredicate{}, false
+	}
+
+	return spatialPredicate{relation: relation, layer: layer, key: parts[2]}, true
+}
@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// geometryQuery describes the geometry the objects of a layer are compared
+// against.
+type geometryQuery struct {
+	// Geometry contains the GeoJSON geometry
+	Geometry json.RawMessage `binding:"required" json:"geometry"`
+
+	// CRS references the coordinate reference system of the geometry.
+	// Defaults to WGS 84 as required by GeoJSON
+	CRS string `json:"crs"`
+
+	// relationParameters select the spatial relation the objects need to
+	// have to the geometry
+	relationParameters
+
+	// Buffer enlarges the geometry by the distance in meters before
+	// comparing it to the objects
+	Buffer float64 `binding:"min=0" json:"buffer"`
+}
+
+// QueryLayerContents returns the objects of the layer having a spatial
+// relation to the geometry supplied in the request body.
+func QueryLayerContents(c *gin.Context) {
+	var parameters geometryQuery
+	if err := c.ShouldBindJSON(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrMissingParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	format, ok := outputFormat(c)
+	if !ok {
+		return
+	}
+
+	relation, ok := parameters.spatialRelation(c)
+	if !ok {
+		return
+	}
+
+	// the geometry is decoded once to reject invalid geometries before
+	// sending them to the database
+	var geometry geom.T
+	err := geojson.Unmarshal(parameters.Geometry, &geometry)
+	if err == nil && geometry == nil {
+		err = errors.New("geometry must not be null")
+	}
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidGeometry
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	crs := 4326
+	if parameters.CRS != "" {
+		crs, ok = resolveCRS(c, parameters.CRS)
+		if !ok {
+			return
+		}
+	}
+
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	contentQuery := layer.ContentQuery()
+	contentQuery.Fields = requestedFields(c)
+	if !filterAttributes(c, contentQuery) || !geometryOutput(c, contentQuery) || !outputCRS(c, contentQuery, c.Query("crs")) {
+		return
+	}
+
+	page, ok := paginate(c, contentQuery, isStreamed(format))
+	if !ok {
+		return
+	}
+
+	input := fmt.Sprintf("ST_SetSRID(ST_GeomFromGeoJSON(%s), %s)",
+		contentQuery.Arg(string(parameters.Geometry)), contentQuery.Arg(crs))
+	if parameters.Buffer > 0 {
+		// buffering the geography allows using meters independent of the
+		// coordinate reference system of the geometry
+		input = fmt.Sprintf("ST_Buffer(ST_Transform(%s, 4326)::geography, %s)::geometry",
+			input, contentQuery.Arg(parameters.Buffer))
+	}
+	contentQuery.Related(relation, fmt.Sprintf("ST_Transform(%s, %d)", input, layer.SRID()))
+
+	if isStreamed(format) {
+		streamObjects(c, format, contentQuery)
+		return
+	}
+
+	objects, ok := selectObjects(c, contentQuery)
+	if !ok {
+		return
+	}
+
+	if !setPaginationHeaders(c, page, contentQuery, objects) {
+		return
+	}
+
+	if len(objects) == 0 && format != formatGeoJSON {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	writeObjects(c, format, objects)
+}
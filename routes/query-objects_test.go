@@ -0,0 +1,107 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"microservice/internal/config"
+	"microservice/middlewares"
+	"microservice/routes"
+)
+
+const queryPolygon = `{"type": "Polygon", "coordinates": [[[7.5, 52.8], [9.0, 52.8], [9.0, 53.8], [7.5, 53.8], [7.5, 52.8]]]}`
+
+func Test_QueryLayerContents(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/-/query", middlewares.ResolveLayer, routes.QueryLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/content/1e694f36-cf68-426a-b6a3-7660163b03e6/-/query", strings.NewReader(`{"geometry": `+queryPolygon+`, "relation": "intersects", "buffer": 500}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_QueryLayerContents_UnsupportedRelation(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/-/query", middlewares.ResolveLayer, routes.QueryLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/content/1e694f36-cf68-426a-b6a3-7660163b03e6/-/query", strings.NewReader(`{"geometry": `+queryPolygon+`, "relation": "touches-nearly"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_QueryLayerContents_InvalidGeometry(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/content/:layerID/-/query", middlewares.ResolveLayer, routes.QueryLayerContents)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/content/1e694f36-cf68-426a-b6a3-7660163b03e6/-/query", strings.NewReader(`{"geometry": {"type": "Circle", "coordinates": [8.2, 53.1]}, "relation": "within"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// de9imPattern matches valid DE-9IM intersection matrix patterns.
+var de9imPattern = regexp.MustCompile(`^[TF012*]{9}$`)
+
+// relationParameters contains the parameters selecting the spatial relation
+// used to filter the objects of a layer. Either a named relation or a DE-9IM
+// pattern needs to be set.
+type relationParameters struct {
+	Relation string   `form:"relation" json:"relation"`
+	Relate   string   `form:"relate"   json:"relate"`
+	Distance *float64 `binding:"omitempty,min=0" form:"distance" json:"distance"`
+}
+
+// spatialRelation converts the parameters into the spatial relation.
+// If the parameters are invalid, the matching error is emitted and false is
+// returned.
+func (p relationParameters) spatialRelation(c *gin.Context) (types.SpatialRelation, bool) {
+	switch {
+	case p.Relation == "" && p.Relate == "":
+		c.Abort()
+		res := apiErrors.ErrMissingParameter
+		res.Errors = []error{errors.New("either relation or relate needs to be set")}
+		res.Emit(c)
+		return types.SpatialRelation{}, false
+	case p.Relation != "" && p.Relate != "":
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{errors.New("relation and relate are mutually exclusive")}
+		res.Emit(c)
+		return types.SpatialRelation{}, false
+	case p.Relate != "":
+		pattern := strings.ToUpper(p.Relate)
+		if !de9imPattern.MatchString(pattern) {
+			c.Abort()
+			res := apiErrors.ErrInvalidParameter
+			res.Errors = []error{errors.New("relate needs to be a DE-9IM pattern consisting of nine characters out of T, F, 0, 1, 2 and *")}
+			res.Emit(c)
+			return types.SpatialRelation{}, false
+		}
+		return types.SpatialRelation{Function: "ST_Relate", Pattern: pattern}, true
+	}
+
+	function, isSupported := types.SpatialRelationFunctions[strings.ToLower(p.Relation)]
+	if !isSupported {
+		c.Abort()
+		apiErrors.ErrUnsupportedSpatialRelation.Emit(c)
+		return types.SpatialRelation{}, false
+	}
+
+	relation := types.SpatialRelation{Function: function}
+	if function == "ST_DWithin" {
+		if p.Distance == nil {
+			c.Abort()
+			res := apiErrors.ErrMissingParameter
+			res.Errors = []error{errors.New("dwithin requires a distance")}
+			res.Emit(c)
+			return types.SpatialRelation{}, false
+		}
+		relation.Distance = *p.Distance
+	}
+
+	return relation, true
+}
@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gin-gonic/gin"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+// searchParameters contains the parameters of a search request.
+type searchParameters struct {
+	Query string `binding:"required"         form:"q"`
+	Limit int    `binding:"omitempty,min=1,max=100" form:"limit"`
+}
+
+// defaultSearchLimit is the number of results returned if no limit is set.
+const defaultSearchLimit = 10
+
+// searchPropertyExpression returns the expression reading the searched
+// additional property of an object. The property is inlined as literal as
+// the expression needs to match the expression index created for the
+// property.
+func searchPropertyExpression(property string) string {
+	return "(additional_properties ->> '" + strings.ReplaceAll(property, "'", "''") + "')"
+}
+
+// Search returns the objects of every accessible layer whose name, key or
+// searchable additional properties match the query ordered by their
+// similarity to the query. The layers may be restricted using the "layer"
+// parameter.
+func Search(c *gin.Context) {
+	var parameters searchParameters
+	if err := c.ShouldBindQuery(&parameters); err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+	if parameters.Limit == 0 {
+		parameters.Limit = defaultSearchLimit
+	}
+
+	layers, ok := requestedLayers(c)
+	if !ok {
+		return
+	}
+
+	results := []types.SearchResult{}
+	if len(layers) == 0 {
+		c.JSON(http.StatusOK, results)
+		return
+	}
+
+	if !checkLayerTables(c, layers) {
+		return
+	}
+
+	query, err := db.Queries.Raw("search-layer-objects")
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	args := []interface{}{
+		parameters.Query,
+		types.ContainsPattern(parameters.Query),
+		types.PrefixPattern(parameters.Query),
+		parameters.Limit,
+	}
+
+	// the layer keys are passed as additional arguments after the shared
+	// arguments while the searched properties of each layer are matched
+	// using their indexed expressions
+	layerQueries := make([]string, len(layers))
+	for idx, layer := range layers {
+		args = append(args, layer.TableName)
+		layerArg := "$" + strconv.Itoa(len(args))
+
+		var scores, conditions strings.Builder
+		for _, property := range layer.SearchProperties {
+			expression := searchPropertyExpression(property)
+			fmt.Fprintf(&scores, ", coalesce(word_similarity($1, %s), 0)", expression)
+			fmt.Fprintf(&conditions, " OR %[1]s ILIKE $2 OR $1 <%% %[1]s", expression)
+		}
+
+		layerQueries[idx] = "(" + fmt.Sprintf(query, layer.TableName, layerArg, scores.String(), conditions.String()) + ")"
+	}
+
+	combinedQuery := strings.Join(layerQueries, " UNION ALL ") + " ORDER BY score DESC, name LIMIT $4"
+	err = pgxscan.Select(c, db.Pool, &results, combinedQuery, args...)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
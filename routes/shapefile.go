@@ -0,0 +1,205 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/internal/shapefile"
+	"microservice/types"
+)
+
+// epsgAuthorityPattern matches the EPSG authority of a well-known text
+// representation of a coordinate reference system. The last match is the
+// authority of the outermost definition.
+var epsgAuthorityPattern = regexp.MustCompile(`AUTHORITY\[\s*"EPSG"\s*,\s*"?(\d+)"?\s*\]`)
+
+// wktNamePattern extracts the name of the outermost definition in a
+// well-known text representation of a coordinate reference system.
+var wktNamePattern = regexp.MustCompile(`^\s*[A-Z_]+\[\s*"([^"]+)"`)
+
+// esriYearPattern matches the years in the datum names used by ESRI (e.g.
+// ETRS_1989) which are abbreviated in the EPSG names (e.g. ETRS89).
+var esriYearPattern = regexp.MustCompile(`_(?:19|20)(\d\d)`)
+
+// esriNameAliases maps the normalized names of ESRI projections that differ
+// completely from the EPSG names to their EPSG code.
+var esriNameAliases = map[string]int{
+	"wgs84webmercatorauxiliarysphere": 3857,
+}
+
+// readShapefile reads the zipped shapefile uploaded in the "shapefile" field
+// of the multipart form. If the shapefile could not be read, the matching
+// error is emitted and false is returned.
+func readShapefile(c *gin.Context) (*shapefile.Archive, bool) {
+	fileHeader, err := c.FormFile("shapefile")
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrMissingParameter
+		res.Errors = []error{err}
+		res.Emit(c)
+		return nil, false
+	}
+
+	fd, err := fileHeader.Open()
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return nil, false
+	}
+	defer fd.Close()
+
+	archive, err := shapefile.Read(fd, fileHeader.Size)
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidShapefile
+		res.Errors = []error{err}
+		res.Emit(c)
+		return nil, false
+	}
+
+	return archive, true
+}
+
+// detectEPSGCode resolves the EPSG code of the shapefile's projection.
+// It uses the EPSG authority contained in the projection if available and
+// falls back to comparing the projection name with the coordinate reference
+// systems known to the database.
+// If the EPSG code could not be detected, 0 is returned.
+func detectEPSGCode(ctx context.Context, projection string) (int, error) {
+	if projection == "" {
+		return 0, nil
+	}
+
+	authorities := epsgAuthorityPattern.FindAllStringSubmatch(projection, -1)
+	if len(authorities) > 0 {
+		return strconv.Atoi(authorities[len(authorities)-1][1])
+	}
+
+	name := wktNamePattern.FindStringSubmatch(projection)
+	if name == nil {
+		return 0, nil
+	}
+
+	normalizedName := strings.ToLower(strings.TrimPrefix(name[1], "GCS_"))
+	normalizedName = esriYearPattern.ReplaceAllString(normalizedName, "$1")
+	normalizedName = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, normalizedName)
+
+	if epsgCode, isAlias := esriNameAliases[normalizedName]; isAlias {
+		return epsgCode, nil
+	}
+
+	query, err := db.Queries.Raw("get-crs-by-name")
+	if err != nil {
+		return 0, err
+	}
+
+	var epsgCode int
+	err = db.Pool.QueryRow(ctx, query, normalizedName).Scan(&epsgCode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return epsgCode, err
+}
+
+// shapefileEPSGCode returns the EPSG code set in the "epsg" parameter of the
+// request or the EPSG code detected from the shapefile's projection. 0 is
+// returned if the EPSG code cannot be detected.
+// If the EPSG code is invalid or not known to the database, the matching
+// error is emitted and false is returned.
+func shapefileEPSGCode(c *gin.Context, archive *shapefile.Archive) (int, bool) {
+	if rawEPSGCode := c.PostForm("epsg"); rawEPSGCode != "" {
+		return resolveCRS(c, rawEPSGCode)
+	}
+
+	epsgCode, err := detectEPSGCode(c, archive.Projection)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return 0, false
+	}
+
+	if epsgCode == 0 {
+		return 0, true
+	}
+
+	// the authority contained in the projection may reference a coordinate
+	// reference system which is not known to the database
+	exists, err := crsExists(c, epsgCode)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return 0, false
+	}
+
+	if !exists {
+		c.Abort()
+		res := apiErrors.ErrUnknownCRS
+		res.Errors = []error{fmt.Errorf("EPSG:%d is not known to the database", epsgCode)}
+		res.Emit(c)
+		return 0, false
+	}
+
+	return epsgCode, true
+}
+
+func IntrospectShapefile(c *gin.Context) {
+	archive, ok := readShapefile(c)
+	if !ok {
+		return
+	}
+
+	report := types.Shapefile{
+		FeatureCount: len(archive.Features),
+		Attributes:   make(map[string]int, len(archive.Fields)),
+		GeometryType: archive.GeometryType,
+	}
+
+	for _, field := range archive.Fields {
+		report.Attributes[field] = 0
+	}
+	for _, feature := range archive.Features {
+		for attribute, value := range feature.Attributes {
+			if value != nil {
+				report.Attributes[attribute]++
+			}
+		}
+	}
+
+	report.EPSGCode, ok = shapefileEPSGCode(c, archive)
+	if !ok {
+		return
+	}
+
+	if report.EPSGCode != 0 {
+		query, err := db.Queries.Raw("get-crs-proj4")
+		if err != nil {
+			c.Abort()
+			_ = c.Error(err)
+			return
+		}
+
+		err = db.Pool.QueryRow(c, query, report.EPSGCode).Scan(&report.Proj4String)
+		if err != nil {
+			c.Abort()
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, report)
+}
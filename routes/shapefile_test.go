@@ -0,0 +1,120 @@
+package routes_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"microservice/internal/config"
+	"microservice/routes"
+)
+
+func Test_IntrospectShapefile_MissingFile(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/shapefile", routes.IntrospectShapefile)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/shapefile", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_IntrospectShapefile_InvalidArchive(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/shapefile", routes.IntrospectShapefile)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, _ := form.CreateFormFile("shapefile", "shapefile.zip")
+	_, _ = file.Write([]byte("this is not a zip archive"))
+	_ = form.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/shapefile", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
+
+func Test_ImportShapefile_MissingFields(t *testing.T) {
+	router := gin.New()
+	router.Use(config.Middlewares()...)
+	router.POST("/shapefile/import", routes.ImportShapefile)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("layer", "1e694f36-cf68-426a-b6a3-7660163b03e6")
+	_ = form.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/shapefile/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if t.Failed() {
+		t.Log(w.Body.String())
+	}
+
+	valid, validationErrors := v.ValidateHttpResponse(req, w.Result())
+	if !valid {
+		t.Fail()
+		for _, e := range validationErrors {
+			t.Logf("Type: %s, Failure: %s\n", e.ValidationType, e.Message)
+			if e.SchemaValidationErrors != nil {
+				t.Logf("Schema Error: %s, Line: %d, Col: %d\n",
+					e.SchemaValidationErrors[0].Reason,
+					e.SchemaValidationErrors[0].Line,
+					e.SchemaValidationErrors[0].Column)
+			}
+		}
+	}
+}
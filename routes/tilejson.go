@@ -0,0 +1,200 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gin-gonic/gin"
+
+	"microservice/internal/db"
+	"microservice/types"
+)
+
+const tileJSONVersion = "3.0.0"
+
+// propertyTypes contains the JSON types used by an additional property in
+// the objects of a layer.
+type propertyTypes struct {
+	Key   string   `db:"key"`
+	Types []string `db:"types"`
+}
+
+// tileFieldType converts the JSON types of an additional property into the
+// field type used by TileJSON. Properties using multiple types are encoded
+// as strings in the tiles.
+func tileFieldType(jsonTypes []string) string {
+	if len(jsonTypes) != 1 {
+		return "String"
+	}
+	switch jsonTypes[0] {
+	case "number":
+		return "Number"
+	case "boolean":
+		return "Boolean"
+	default:
+		return "String"
+	}
+}
+
+// describeVectorLayer describes the layer as contained in the vector tiles. If no
+// properties are requested, all additional properties of the layer are
+// included in the tiles.
+// The returned properties contain the additional properties included in the
+// tiles.
+func describeVectorLayer(c *gin.Context, layer types.Layer, requested []string) (types.VectorLayer, []string, error) {
+	query, err := db.Queries.Raw("get-layer-property-types")
+	if err != nil {
+		return types.VectorLayer{}, nil, err
+	}
+
+	var properties []propertyTypes
+	err = pgxscan.Select(c, db.Pool, &properties, fmt.Sprintf(query, layer.TableName))
+	if err != nil {
+		return types.VectorLayer{}, nil, err
+	}
+
+	description := layer.Name
+	if layer.Description.Valid {
+		description = layer.Description.String
+	}
+
+	vectorLayer := types.VectorLayer{
+		ID:          layer.TableName,
+		Description: description,
+		MinZoom:     0,
+		MaxZoom:     maxZoom,
+		Fields: map[string]string{
+			"key":  "String",
+			"name": "String",
+		},
+	}
+
+	var included []string
+	for _, property := range properties {
+		if len(requested) > 0 && !slices.Contains(requested, property.Key) {
+			continue
+		}
+		if _, isColumn := vectorLayer.Fields[property.Key]; isColumn {
+			continue
+		}
+		vectorLayer.Fields[property.Key] = tileFieldType(property.Types)
+		included = append(included, property.Key)
+	}
+	slices.Sort(included)
+
+	return vectorLayer, included, nil
+}
+
+// tileURL returns the URL template of the tiles for the tile set.
+func tileURL(c *gin.Context, path string, parameters url.Values) string {
+	template := serviceBaseURL(c) + "/tiles/" + path + "/{z}/{x}/{y}.mvt"
+	if len(parameters) > 0 {
+		template += "?" + parameters.Encode()
+	}
+	return template
+}
+
+// extendBounds extends the bounds by the extent of the layer.
+func extendBounds(bounds []float64, extent types.Extent) []float64 {
+	bbox := extent.Spatial.BBox[0]
+	if bounds == nil {
+		return bbox[:]
+	}
+	return []float64{
+		min(bounds[0], bbox[0]),
+		min(bounds[1], bbox[1]),
+		max(bounds[2], bbox[2]),
+		max(bounds[3], bbox[3]),
+	}
+}
+
+// tileSet generates the TileJSON document for the layers. The tiles contain
+// the requested additional properties or all additional properties if none
+// have been requested.
+func tileSet(c *gin.Context, layers []types.Layer, path string, parameters url.Values) (types.TileJSON, error) {
+	requested := tileProperties(c)
+	tileJSON := types.TileJSON{
+		TileJSON:     tileJSONVersion,
+		Scheme:       "xyz",
+		MinZoom:      0,
+		MaxZoom:      maxZoom,
+		VectorLayers: make([]types.VectorLayer, len(layers)),
+	}
+
+	var attributions, properties []string
+	for idx, layer := range layers {
+		vectorLayer, included, err := describeVectorLayer(c, layer, requested)
+		if err != nil {
+			return types.TileJSON{}, err
+		}
+		tileJSON.VectorLayers[idx] = vectorLayer
+		for _, property := range included {
+			if !slices.Contains(properties, property) {
+				properties = append(properties, property)
+			}
+		}
+
+		extent, hasExtent, err := layerExtent(c, layer)
+		if err != nil {
+			return types.TileJSON{}, err
+		}
+		if hasExtent {
+			tileJSON.Bounds = extendBounds(tileJSON.Bounds, extent)
+		}
+
+		if layer.Attribution.Valid && !slices.Contains(attributions, layer.Attribution.String) {
+			attributions = append(attributions, layer.Attribution.String)
+		}
+	}
+
+	if len(properties) > 0 {
+		parameters.Set("properties", strings.Join(properties, ","))
+	}
+	tileJSON.Tiles = []string{tileURL(c, path, parameters)}
+	tileJSON.Attribution = strings.Join(attributions, ", ")
+	return tileJSON, nil
+}
+
+func LayerTileJSON(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	tileJSON, err := tileSet(c, []types.Layer{layer}, layer.TableName, url.Values{})
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	tileJSON.Name = layer.Name
+	tileJSON.Description = layer.Description.String
+	c.JSON(http.StatusOK, tileJSON)
+}
+
+func CompositeTileJSON(c *gin.Context) {
+	layers, ok := tileLayers(c)
+	if !ok {
+		return
+	}
+
+	parameters := url.Values{}
+	names := make([]string, len(layers))
+	for idx, layer := range layers {
+		parameters.Add("layer", layer.TableName)
+		names[idx] = layer.Name
+	}
+
+	tileJSON, err := tileSet(c, layers, "composite", parameters)
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	tileJSON.Name = strings.Join(names, ", ")
+	c.JSON(http.StatusOK, tileJSON)
+}
@@ -0,0 +1,167 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"microservice/internal/db"
+	apiErrors "microservice/internal/errors"
+	"microservice/types"
+)
+
+const mimeMVT = "application/vnd.mapbox-vector-tile"
+
+// maxZoom is the highest zoom level tiles are generated for.
+const maxZoom = 24
+
+// tileCoordinates identifies a tile in the Web Mercator tile matrix.
+type tileCoordinates struct {
+	Z int
+	X int
+	Y int
+}
+
+// parseTileCoordinates reads the coordinates of the requested tile from the
+// path. The y coordinate may contain the ".mvt" extension.
+func parseTileCoordinates(c *gin.Context) (tileCoordinates, error) {
+	z, err := strconv.Atoi(c.Param("z"))
+	if err != nil {
+		return tileCoordinates{}, err
+	}
+	x, err := strconv.Atoi(c.Param("x"))
+	if err != nil {
+		return tileCoordinates{}, err
+	}
+	y, err := strconv.Atoi(strings.TrimSuffix(c.Param("y"), ".mvt"))
+	if err != nil {
+		return tileCoordinates{}, err
+	}
+
+	if z < 0 || z > maxZoom {
+		return tileCoordinates{}, fmt.Errorf("zoom level needs to be between 0 and %d", maxZoom)
+	}
+	tiles := 1 << z
+	if x < 0 || x >= tiles || y < 0 || y >= tiles {
+		return tileCoordinates{}, errors.New("tile is outside of the tile matrix")
+	}
+
+	return tileCoordinates{Z: z, X: x, Y: y}, nil
+}
+
+// tileProperties returns the additional properties requested as tile
+// attributes in the "properties" parameter. The parameter may be repeated or
+// contain a comma-separated list.
+func tileProperties(c *gin.Context) []string {
+	properties := []string{}
+	for _, value := range c.QueryArray("properties") {
+		for _, property := range strings.Split(value, ",") {
+			if property = strings.TrimSpace(property); property != "" {
+				properties = append(properties, property)
+			}
+		}
+	}
+	return properties
+}
+
+// renderTile renders the objects of the layer into a Mapbox Vector Tile. The
+// layer key is used as name of the layer inside the tile.
+func renderTile(c *gin.Context, layer types.Layer, tile tileCoordinates, properties []string) ([]byte, error) {
+	query, err := db.Queries.Raw("get-layer-tile")
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []byte
+	err = db.Pool.QueryRow(c, fmt.Sprintf(query, layer.TableName, layer.SRID()),
+		tile.Z, tile.X, tile.Y, layer.TableName, properties).Scan(&contents)
+	return contents, err
+}
+
+func LayerTile(c *gin.Context) {
+	layerInterface, _ := c.Get("layer")
+	layer, _ := layerInterface.(types.Layer)
+
+	tile, err := parseTileCoordinates(c)
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidTile
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	contents, err := renderTile(c, layer, tile, tileProperties(c))
+	if err != nil {
+		c.Abort()
+		_ = c.Error(err)
+		return
+	}
+
+	if len(contents) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.Data(http.StatusOK, mimeMVT, contents)
+}
+
+// tileLayers resolves the layers combined in a composite tile which are set
+// in the "layer" parameter.
+// If no layer is set or a layer is unknown or not accessible, the matching
+// error is emitted and false is returned.
+func tileLayers(c *gin.Context) ([]types.Layer, bool) {
+	references := layerReferences(c)
+	if len(references) == 0 {
+		c.Abort()
+		res := apiErrors.ErrMissingParameter
+		res.Errors = []error{errors.New("at least one layer needs to be set")}
+		res.Emit(c)
+		return nil, false
+	}
+
+	return resolveLayers(c, references)
+}
+
+// CompositeTile combines the tiles of multiple layers into a single Mapbox
+// Vector Tile. Each layer is contained as separate layer in the tile.
+func CompositeTile(c *gin.Context) {
+	tile, err := parseTileCoordinates(c)
+	if err != nil {
+		c.Abort()
+		res := apiErrors.ErrInvalidTile
+		res.Errors = []error{err}
+		res.Emit(c)
+		return
+	}
+
+	layers, ok := tileLayers(c)
+	if !ok {
+		return
+	}
+
+	// vector tiles are protocol buffer messages with repeated layers and can
+	// therefore be combined by concatenating the tiles of the single layers
+	properties := tileProperties(c)
+	var contents []byte
+	for _, layer := range layers {
+		layerContents, err := renderTile(c, layer, tile, properties)
+		if err != nil {
+			c.Abort()
+			_ = c.Error(err)
+			return
+		}
+		contents = append(contents, layerContents...)
+	}
+
+	if len(contents) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.Data(http.StatusOK, mimeMVT, contents)
+}
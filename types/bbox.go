@@ -0,0 +1,13 @@
+package types
+
+// BoundingBox is a rectangular area in a coordinate reference system.
+type BoundingBox struct {
+	MinX float64
+	MinY float64
+	MaxX float64
+	MaxY float64
+
+	// CRS contains the EPSG code of the coordinate reference system the
+	// coordinates are expressed in
+	CRS int
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+)
+
+// Feature is the GeoJSON representation of an Object.
+// The key, the name and the additional properties of the object are contained
+// in the properties of the feature.
+type Feature struct {
+	ID         uint64
+	Geometry   geom.T
+	Properties map[string]interface{}
+
+	// Links contains links to related resources which are only set by the
+	// OGC API - Features endpoints
+	Links []Link
+
+	// precision contains the maximal number of decimal digits used for the
+	// coordinates of the geometry
+	precision *int
+}
+
+// _feature is used as the marshaling object for the Feature as the geometry
+// is manually encoded.
+type _feature struct {
+	Type       string                 `json:"type"`
+	ID         uint64                 `json:"id"`
+	Geometry   json.RawMessage        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+	Links      []Link                 `json:"links,omitempty"`
+}
+
+// MarshalJSON implements the [json.Marshaler] interface and outputs the
+// feature as a GeoJSON Feature.
+func (f Feature) MarshalJSON() ([]byte, error) {
+	output := _feature{
+		Type:       "Feature",
+		ID:         f.ID,
+		Properties: f.Properties,
+		Links:      f.Links,
+	}
+	output.Geometry, _ = geojson.Marshal(f.Geometry, geojson.EncodeGeometryWithBBox(), geojson.EncodeGeometryWithMaxDecimalDigits(decimalDigits(f.precision)))
+	return json.Marshal(output)
+}
+
+// Feature converts the object into a GeoJSON Feature. The key and name of the
+// object take precedence over additional properties with the same name. The
+// name is only contained if it has been selected.
+func (o Object) Feature() Feature {
+	properties := make(map[string]interface{}, len(o.AdditionalProperties)+2)
+	for property, value := range o.AdditionalProperties {
+		properties[property] = value
+	}
+	properties["key"] = o.Key
+	if !o.omitName {
+		properties["name"] = o.Name
+	}
+
+	return Feature{
+		ID:         o.ID,
+		Geometry:   o.Geometry,
+		Properties: properties,
+		precision:  o.precision,
+	}
+}
+
+// FeatureCollection is the GeoJSON representation of multiple objects.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// NewFeatureCollection converts the objects into a GeoJSON FeatureCollection.
+func NewFeatureCollection(objects []Object) FeatureCollection {
+	collection := FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]Feature, len(objects)),
+	}
+	for idx, object := range objects {
+		collection.Features[idx] = object.Feature()
+	}
+	return collection
+}
@@ -1,11 +1,9 @@
 package types
 
 import (
-	"fmt"
+	"encoding/json"
 
 	"github.com/jackc/pgx/v5/pgtype"
-
-	"microservice/internal/db"
 )
 
 // Layer represents an entry in the "layers" table.
@@ -17,20 +15,42 @@ type Layer struct {
 	Attribution               pgtype.Text `db:"attribution" json:"attribution"`
 	CoordinateReferenceSystem pgtype.Int4 `db:"crs"         json:"crs"`
 	Private                   bool        `db:"private"     json:"private"`
+	ChildLayer                pgtype.UUID `db:"child_layer" json:"childLayer"`
+
+	// SearchProperties contains the names of the additional properties which
+	// are searched in addition to the name and the key of the objects
+	SearchProperties []string `db:"search_properties" json:"searchProperties"`
+
+	// Schema contains the JSON Schema of the additional properties pinned by
+	// the maintainers of the layer. It overrides the inferred schema and is
+	// only returned by the schema endpoint
+	Schema json.RawMessage `db:"schema" json:"-"`
+
+	// SupportedCRS lists the coordinate reference systems advertised for the
+	// output of the layer's objects. It is not stored in the database but
+	// set by the routes returning the layer
+	SupportedCRS []string `db:"-" json:"supportedCrs,omitempty"`
 }
 
-func (l Layer) ContentQuery() (string, error) {
-	rawQuery, err := db.Queries.Raw("get-layer-contents")
-	if err != nil {
-		return "", err
+// SRID returns the EPSG code of the coordinate reference system the layer's
+// geometries are stored in. Layers without a configured coordinate reference
+// system are assumed to use WGS 84.
+func (l Layer) SRID() int {
+	if !l.CoordinateReferenceSystem.Valid {
+		return 4326
 	}
-	return fmt.Sprintf(rawQuery, l.TableName), nil
+	return int(l.CoordinateReferenceSystem.Int32)
 }
 
-func (l Layer) FilteredContentQuery() (string, error) {
-	rawQuery, err := db.Queries.Raw("get-layer-object-by-key")
-	if err != nil {
-		return "", err
-	}
-	return fmt.Sprintf(rawQuery, l.TableName), nil
+// ContentQuery returns a new query selecting the objects of the layer.
+func (l Layer) ContentQuery() *ObjectQuery {
+	return &ObjectQuery{layer: l}
+}
+
+// FilteredContentQuery returns a new query selecting the objects of the layer
+// with the key.
+func (l Layer) FilteredContentQuery(key string) *ObjectQuery {
+	q := l.ContentQuery()
+	q.Where("key = " + q.Arg(key) + "::text")
+	return q
 }
@@ -17,6 +17,40 @@ type Object struct {
 	Name                 *string                `db:"name"                  json:"name"`
 	Key                  string                 `db:"key"                   json:"key"`
 	AdditionalProperties map[string]interface{} `db:"additional_properties" json:"additionalProperties"`
+
+	// precision contains the maximal number of decimal digits used for the
+	// coordinates of the geometry. DefaultPrecision is used if it is nil
+	precision *int
+
+	// omitName removes the name from the output if it has not been selected
+	omitName bool
+}
+
+// DefaultPrecision is the maximal number of decimal digits used for the
+// coordinates of geometries if no other precision has been requested.
+const DefaultPrecision = 15
+
+// WithPrecision returns a copy of the object which encodes the coordinates of
+// its geometry with at most the number of decimal digits.
+func (o Object) WithPrecision(digits int) Object {
+	o.precision = &digits
+	return o
+}
+
+// WithoutName returns a copy of the object which does not contain the name in
+// its output.
+func (o Object) WithoutName() Object {
+	o.omitName = true
+	return o
+}
+
+// decimalDigits returns the maximal number of decimal digits used for the
+// coordinates of the geometry.
+func decimalDigits(precision *int) int {
+	if precision == nil {
+		return DefaultPrecision
+	}
+	return *precision
 }
 
 // _object is used as the marshaling object for the Object as the geometry
@@ -41,6 +75,14 @@ func (o Object) MarshalJSON() ([]byte, error) {
 		Key:                  o.Key,
 		AdditionalProperties: o.AdditionalProperties,
 	}
-	output.Geometry, _ = geojson.Marshal(o.Geometry, geojson.EncodeGeometryWithBBox(), geojson.EncodeGeometryWithMaxDecimalDigits(15))
+	output.Geometry, _ = geojson.Marshal(o.Geometry, geojson.EncodeGeometryWithBBox(), geojson.EncodeGeometryWithMaxDecimalDigits(decimalDigits(o.precision)))
+	if o.omitName {
+		// the name of the outer struct shadows the name of the embedded
+		// struct and is omitted as it is always nil
+		return json.Marshal(struct {
+			_object
+			Name *string `json:"name,omitempty"`
+		}{_object: output})
+	}
 	return json.Marshal(output)
 }
@@ -0,0 +1,71 @@
+package types
+
+import "time"
+
+// Link is a link to a related resource as used by OGC API - Features.
+type Link struct {
+	Href  string `json:"href"`
+	Rel   string `json:"rel"`
+	Type  string `json:"type,omitempty"`
+	Title string `json:"title,omitempty"`
+}
+
+// LandingPage is the entry point of the OGC API - Features endpoints.
+type LandingPage struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Links       []Link `json:"links"`
+}
+
+// Conformance lists the conformance classes implemented by the OGC API -
+// Features endpoints.
+type Conformance struct {
+	ConformsTo []string `json:"conformsTo"`
+}
+
+// Extent describes the spatial extent of a collection.
+type Extent struct {
+	Spatial SpatialExtent `json:"spatial"`
+}
+
+// SpatialExtent contains the bounding box of all objects of a collection.
+type SpatialExtent struct {
+	BBox [][4]float64 `json:"bbox"`
+	CRS  string       `json:"crs"`
+}
+
+// Collection is the OGC API - Features representation of a layer.
+type Collection struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Attribution string  `json:"attribution,omitempty"`
+	Links       []Link  `json:"links"`
+	Extent      *Extent `json:"extent,omitempty"`
+	ItemType    string  `json:"itemType"`
+
+	// CRS lists the coordinate reference systems the features may be
+	// requested in
+	CRS []string `json:"crs"`
+
+	// StorageCRS is the coordinate reference system the features are stored
+	// in
+	StorageCRS string `json:"storageCrs"`
+}
+
+// Collections lists the collections available in the OGC API - Features
+// endpoints.
+type Collections struct {
+	Links       []Link       `json:"links"`
+	Collections []Collection `json:"collections"`
+}
+
+// ItemCollection is a GeoJSON FeatureCollection extended by the members
+// required by OGC API - Features.
+type ItemCollection struct {
+	FeatureCollection
+	Links          []Link    `json:"links"`
+	TimeStamp      time.Time `json:"timeStamp"`
+	NumberMatched  int       `json:"numberMatched"`
+	NumberReturned int       `json:"numberReturned"`
+}
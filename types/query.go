@@ -0,0 +1,242 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"microservice/internal/db"
+)
+
+// ObjectQuery builds the query selecting the objects of a layer.
+// Conditions are added using Where and the values used in the conditions are
+// passed as query parameters which are registered using Arg.
+// The objects are always ordered by their id to allow a stable pagination.
+type ObjectQuery struct {
+	layer      Layer
+	conditions []string
+	args       []interface{}
+
+	// Limit restricts the number of selected objects if it is greater than 0
+	Limit int
+
+	// Offset skips the first objects
+	Offset int
+
+	// After only selects the objects with an id greater than the value if
+	// it is greater than 0
+	After uint64
+
+	// CRS contains the EPSG code of the coordinate reference system the
+	// geometries are returned in. Defaults to WGS 84
+	CRS int
+
+	// Precision contains the maximal number of decimal digits used for the
+	// coordinates of the selected objects. It is not used in the query itself
+	// but applied to the objects after selecting them
+	Precision *int
+
+	// Geometry selects the representation of the geometries. The full
+	// geometries are returned if it is empty
+	Geometry GeometryMode
+
+	// Fields restricts the properties of the selected objects to the listed
+	// ones if it is not nil. The id and the key are always selected
+	Fields []string
+
+	// tolerance is used to simplify the geometries if it is greater than 0.
+	// The tolerance is expressed in units of the output coordinate reference
+	// system unless webMercator is set
+	tolerance   float64
+	webMercator bool
+}
+
+// GeometryMode selects the representation of the geometries returned by an
+// ObjectQuery.
+type GeometryMode string
+
+const (
+	GeometryFull        GeometryMode = "full"
+	GeometryNone        GeometryMode = "none"
+	GeometryCentroid    GeometryMode = "centroid"
+	GeometryBoundingBox GeometryMode = "bbox"
+)
+
+// webMercatorResolution is the size of a pixel in meters at zoom level 0 of
+// the Web Mercator tile matrix using 256 pixel tiles.
+const webMercatorResolution = 156543.03392804097
+
+// Simplify simplifies the geometries while preserving their topology. The
+// tolerance is expressed in units of the coordinate reference system the
+// geometries are returned in.
+func (q *ObjectQuery) Simplify(tolerance float64) {
+	q.tolerance = tolerance
+	q.webMercator = false
+}
+
+// SimplifyForZoom simplifies the geometries to the size of a pixel at the zoom
+// level of the Web Mercator tile matrix while preserving their topology.
+func (q *ObjectQuery) SimplifyForZoom(zoom int) {
+	q.tolerance = webMercatorResolution / float64(uint64(1)<<zoom)
+	q.webMercator = true
+}
+
+// ApplyOutput sets the precision of the query on the object and removes the
+// name from the output if it has not been selected.
+func (q *ObjectQuery) ApplyOutput(object Object) Object {
+	if q.Fields != nil && !slices.Contains(q.Fields, "name") {
+		object = object.WithoutName()
+	}
+	if q.Precision == nil {
+		return object
+	}
+	return object.WithPrecision(*q.Precision)
+}
+
+// geometry returns the expression selecting the geometry in the coordinate
+// reference system.
+func (q *ObjectQuery) geometry(crs int) string {
+	switch q.Geometry {
+	case GeometryNone:
+		return "NULL::geometry"
+	case GeometryCentroid:
+		return fmt.Sprintf("ST_Transform(ST_Centroid(geometry), %d)", crs)
+	case GeometryBoundingBox:
+		return fmt.Sprintf("ST_Envelope(ST_Transform(geometry, %d))", crs)
+	}
+
+	tolerance := strconv.FormatFloat(q.tolerance, 'g', -1, 64)
+	switch {
+	case q.tolerance > 0 && q.webMercator:
+		return fmt.Sprintf("ST_Transform(ST_SimplifyPreserveTopology(ST_Transform(geometry, 3857), %s), %d)", tolerance, crs)
+	case q.tolerance > 0:
+		return fmt.Sprintf("ST_SimplifyPreserveTopology(ST_Transform(geometry, %d), %s)", crs, tolerance)
+	default:
+		return fmt.Sprintf("ST_Transform(geometry, %d)", crs)
+	}
+}
+
+// name returns the expression selecting the name of the objects.
+func (q *ObjectQuery) name() string {
+	if q.Fields == nil || slices.Contains(q.Fields, "name") {
+		return "name"
+	}
+	return "NULL::text"
+}
+
+// additionalProperties returns the expression selecting the additional
+// properties of the objects which are contained in the fields.
+// The field names are embedded as literals instead of query parameters as
+// the query counting the objects shares the parameters but does not select
+// the properties.
+func (q *ObjectQuery) additionalProperties() string {
+	if q.Fields == nil {
+		return "additional_properties"
+	}
+
+	literals := make([]string, len(q.Fields))
+	for idx, field := range q.Fields {
+		literals[idx] = "'" + strings.ReplaceAll(field, "'", "''") + "'"
+	}
+	return fmt.Sprintf("(SELECT coalesce(jsonb_object_agg(p.name, p.value), '{}'::jsonb) FROM jsonb_each(additional_properties) AS p(name, value) WHERE p.name = ANY(ARRAY[%s]::text[]))",
+		strings.Join(literals, ", "))
+}
+
+// Arg registers the value as query parameter and returns the placeholder
+// which needs to be used in the condition.
+func (q *ObjectQuery) Arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+// Args returns the values of the query parameters.
+func (q *ObjectQuery) Args() []interface{} {
+	return q.args
+}
+
+// Where adds a condition to the query. All conditions need to be fulfilled
+// by the selected objects.
+func (q *ObjectQuery) Where(condition string) {
+	q.conditions = append(q.conditions, "("+condition+")")
+}
+
+// KeyPrefix restricts the query to the objects whose key starts with the
+// prefix. This allows resolving hierarchical keys like the official
+// municipality keys without spatial queries.
+func (q *ObjectQuery) KeyPrefix(prefix string) {
+	q.Where("key LIKE " + q.Arg(PrefixPattern(prefix)))
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// PrefixPattern returns the LIKE pattern matching all values starting with
+// the prefix. Using LIKE instead of starts_with allows the usage of indexes
+// using the text_pattern_ops operator class.
+func PrefixPattern(prefix string) string {
+	return likeEscaper.Replace(prefix) + "%"
+}
+
+// ContainsPattern returns the LIKE pattern matching all values containing
+// the value.
+func ContainsPattern(value string) string {
+	return "%" + likeEscaper.Replace(value) + "%"
+}
+
+// Intersects restricts the query to the objects intersecting the bounding
+// box. The bounding box is transformed into the coordinate reference system
+// of the layer to allow the usage of the spatial index.
+func (q *ObjectQuery) Intersects(bbox BoundingBox) {
+	envelope := fmt.Sprintf("ST_MakeEnvelope(%s, %s, %s, %s, %s)",
+		q.Arg(bbox.MinX), q.Arg(bbox.MinY), q.Arg(bbox.MaxX), q.Arg(bbox.MaxY), q.Arg(bbox.CRS))
+	q.Where(fmt.Sprintf("ST_Intersects(geometry, ST_Transform(%s, %d))", envelope, q.layer.SRID()))
+}
+
+func (q *ObjectQuery) where(conditions ...string) string {
+	conditions = slices.Concat(q.conditions, conditions)
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// SQL returns the query selecting the objects.
+func (q *ObjectQuery) SQL() (string, error) {
+	rawQuery, err := db.Queries.Raw("get-layer-contents")
+	if err != nil {
+		return "", err
+	}
+
+	var conditions []string
+	if q.After > 0 {
+		conditions = append(conditions, fmt.Sprintf("id > %d", q.After))
+	}
+
+	crs := q.CRS
+	if crs == 0 {
+		crs = 4326
+	}
+
+	query := strings.TrimSuffix(strings.TrimSpace(fmt.Sprintf(rawQuery, q.layer.TableName, q.geometry(crs), q.name(), q.additionalProperties())), ";")
+	query += q.where(conditions...) + " ORDER BY id"
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+	if q.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", q.Offset)
+	}
+	return query, nil
+}
+
+// CountSQL returns the query counting the objects matching the conditions.
+// The pagination of the query is ignored.
+func (q *ObjectQuery) CountSQL() (string, error) {
+	rawQuery, err := db.Queries.Raw("count-layer-contents")
+	if err != nil {
+		return "", err
+	}
+
+	query := strings.TrimSuffix(strings.TrimSpace(fmt.Sprintf(rawQuery, q.layer.TableName)), ";")
+	return query + q.where(), nil
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SpatialRelationFunctions maps the names of the supported spatial relations
+// to the PostGIS functions checking if an object of a layer has the relation
+// to another geometry. The names are stored in lower case.
+var SpatialRelationFunctions = map[string]string{
+	"intersects": "ST_Intersects",
+	"touches":    "ST_Touches",
+	"crosses":    "ST_Crosses",
+	"disjoint":   "ST_Disjoint",
+	"equals":     "ST_Equals",
+	"covers":     "ST_Covers",
+	"coveredby":  "ST_CoveredBy",
+	"within":     "ST_Within",
+	"overlaps":   "ST_Overlaps",
+	"contains":   "ST_Contains",
+	"dwithin":    "ST_DWithin",
+}
+
+// SpatialRelation describes the spatial relation the objects of a layer need
+// to have to another geometry.
+type SpatialRelation struct {
+	// Function is the PostGIS function checking the relation
+	Function string
+
+	// Distance is the maximal distance in meters between the geometries
+	// which is only used by ST_DWithin
+	Distance float64
+
+	// Pattern is the DE-9IM intersection matrix pattern which is only used
+	// by ST_Relate
+	Pattern string
+}
+
+// Condition returns the condition checking the relation between the geometry
+// of an object and the other geometry. The values used in the condition are
+// registered as arguments of the query.
+func (r SpatialRelation) Condition(q *ObjectQuery, other string) string {
+	switch r.Function {
+	case "ST_DWithin":
+		// the distance is measured on the spheroid to support meters
+		// independent of the coordinate reference system of the layer.
+		// As the transformed geometries cannot use the spatial index, the
+		// objects are prefiltered using the bounding box of the buffered
+		// geometry which is enlarged slightly as the buffer only
+		// approximates the distance
+		return fmt.Sprintf("(geometry && ST_Transform(ST_Buffer(ST_Transform(%[1]s, 4326)::geography, %[2]s)::geometry, %[3]d) AND ST_DWithin(ST_Transform(geometry, 4326)::geography, ST_Transform(%[1]s, 4326)::geography, %[4]s))",
+			other, q.Arg(r.Distance*1.1), q.layer.SRID(), q.Arg(r.Distance))
+	case "ST_Relate":
+		return fmt.Sprintf("ST_Relate(geometry, %s, %s)", other, q.Arg(r.Pattern))
+	default:
+		return fmt.Sprintf("%s(geometry, %s)", r.Function, other)
+	}
+}
+
+// Related restricts the query to the objects having the spatial relation to
+// at least one of the other geometries. The other geometries are SQL
+// expressions which need to use the coordinate reference system of the layer.
+func (q *ObjectQuery) Related(relation SpatialRelation, others ...string) {
+	conditions := make([]string, len(others))
+	for idx, other := range others {
+		conditions[idx] = relation.Condition(q, other)
+	}
+	q.Where(strings.Join(conditions, " OR "))
+}
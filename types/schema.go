@@ -0,0 +1,19 @@
+package types
+
+import "encoding/json"
+
+// JSONSchemaDialect is the dialect of the JSON Schema documents describing the
+// additional properties of the objects in a layer.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema contains the subset of the JSON Schema keywords used to describe
+// the additional properties of the objects in a layer.
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        []string               `json:"type,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Enum        []json.RawMessage      `json:"enum,omitempty"`
+}
@@ -0,0 +1,22 @@
+package types
+
+// SearchResult is an object matching a search query.
+type SearchResult struct {
+	// Layer contains the key of the layer containing the object
+	Layer string `db:"layer" json:"layer"`
+
+	// Key contains the key of the object
+	Key string `db:"key" json:"key"`
+
+	// Name contains the name of the object
+	Name *string `db:"name" json:"name"`
+
+	// BBox contains the bounding box of the object's geometry as
+	// [minLon, minLat, maxLon, maxLat]. It is omitted if the object does not
+	// have a geometry
+	BBox []float64 `db:"bbox" json:"bbox,omitempty"`
+
+	// Score contains the similarity of the object to the search query between
+	// 0 and 1
+	Score float64 `db:"score" json:"score"`
+}
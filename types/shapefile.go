@@ -6,6 +6,9 @@ type Shapefile struct {
 	// FeatureCount contains the number of features in the shapefile
 	FeatureCount int `json:"featureCount"`
 
+	// GeometryType contains the type of shapes stored in the shapefile
+	GeometryType string `json:"geometryType"`
+
 	// Attributes is a map which maps attribute names to the number of shapes
 	// on which this attribute exists
 	Attributes map[string]int `json:"attributes"`
@@ -0,0 +1,37 @@
+package types
+
+// LayerStatistics contains statistical information about the objects stored
+// in a layer.
+type LayerStatistics struct {
+	// FeatureCount contains the number of objects in the layer
+	FeatureCount int `json:"featureCount"`
+
+	// UnnamedCount contains the number of objects without a name
+	UnnamedCount int `json:"unnamedCount"`
+
+	// Extent contains the bounding box of all objects. It is nil if the layer
+	// does not contain any geometries
+	Extent *LayerExtent `json:"extent,omitempty"`
+
+	// GeometryTypes maps the geometry types to the number of objects having
+	// a geometry of this type
+	GeometryTypes map[string]int `json:"geometryTypes"`
+
+	// Attributes maps the names of the additional properties to the number of
+	// objects on which this property is set
+	Attributes map[string]int `json:"attributes"`
+}
+
+// LayerExtent contains the bounding box of the objects of a layer in the
+// layer's coordinate reference system and in WGS 84.
+type LayerExtent struct {
+	// Native contains the bounding box as [minX, minY, maxX, maxY] in the
+	// coordinate reference system of the layer
+	Native [4]float64 `json:"native"`
+
+	// CRS contains the EPSG code of the layer's coordinate reference system
+	CRS int `json:"crs"`
+
+	// WGS84 contains the bounding box as [minLon, minLat, maxLon, maxLat]
+	WGS84 [4]float64 `json:"wgs84"`
+}
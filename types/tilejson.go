@@ -0,0 +1,27 @@
+package types
+
+// TileJSON describes a tile set following the TileJSON 3.0.0 specification.
+type TileJSON struct {
+	TileJSON     string        `json:"tilejson"`
+	Name         string        `json:"name,omitempty"`
+	Description  string        `json:"description,omitempty"`
+	Attribution  string        `json:"attribution,omitempty"`
+	Scheme       string        `json:"scheme"`
+	Tiles        []string      `json:"tiles"`
+	MinZoom      int           `json:"minzoom"`
+	MaxZoom      int           `json:"maxzoom"`
+	Bounds       []float64     `json:"bounds,omitempty"`
+	VectorLayers []VectorLayer `json:"vector_layers"`
+}
+
+// VectorLayer describes a layer contained in the vector tiles of a tile set.
+type VectorLayer struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	MinZoom     int    `json:"minzoom"`
+	MaxZoom     int    `json:"maxzoom"`
+
+	// Fields maps the attributes of the features to their type which is
+	// either "String", "Number" or "Boolean"
+	Fields map[string]string `json:"fields"`
+}